@@ -41,12 +41,25 @@ type AuthorizationConfigurationObservation struct {
 type AuthorizationConfigurationSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       AuthorizationConfigurationParameters `json:"forProvider"`
+
+	// IgnoreChanges lists fields of the GoCD representation of the authorization
+	// configuration that are managed outside of this resource, as JSON pointer
+	// like paths, e.g. /properties/Password. Elements of arrays are addressed by
+	// their name, key or id, or by their index. Ignored fields are not compared
+	// and keep the value GoCD has when the authorization configuration is
+	// updated.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^/`
+	IgnoreChanges []string `json:"ignoreChanges,omitempty"`
 }
 
 // An AuthorizationConfigurationStatus represents the observed state of an AuthorizationConfiguration.
 type AuthorizationConfigurationStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          AuthorizationConfigurationObservation `json:"atProvider,omitempty"`
+	// Drift reports how the observed resource differs from spec.
+	// +optional
+	Drift *Drift `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
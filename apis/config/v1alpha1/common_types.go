@@ -2,13 +2,18 @@ package v1alpha1
 
 import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type KeyValue struct {
 	// +kubebuilder:validation:Required
 	Key string `json:"key"`
-	// +kubebuilder:validation:Required
-	Value string `json:"value"`
+	// +kubebuilder:validation:Optional
+	Value string `json:"value,omitempty"`
+	// EncryptedValue is the value as encrypted by the GoCD server, for
+	// properties holding credentials. GoCD only reveals these encrypted.
+	// +kubebuilder:validation:Optional
+	EncryptedValue string `json:"encryptedValue,omitempty"`
 }
 
 func KeyValuesEqual(a, b []KeyValue) bool {
@@ -57,3 +62,55 @@ type ConfigMapKeySelector struct {
 	Namespace string `json:"namespace"`
 	Key       string `json:"key"`
 }
+
+// EnvFromSource imports every key of a Secret or ConfigMap as an environment
+// variable. Variables imported from a Secret are secure. A variable listed in
+// environmentVariables takes precedence over an imported one of the same name,
+// and a key of a later source over the same key of an earlier one.
+type EnvFromSource struct {
+	// Prefix is prepended to the name of every imported variable.
+	// +kubebuilder:validation:Optional
+	Prefix string `json:"prefix,omitempty"`
+	// Secure marks the variables imported from a ConfigMap secure.
+	// +kubebuilder:validation:Optional
+	Secure bool `json:"secure,omitempty"`
+	// ConfigMapRef references the ConfigMap to import.
+	// +kubebuilder:validation:Optional
+	ConfigMapRef *ConfigMapReference `json:"configMapRef,omitempty"`
+	// SecretRef references the Secret to import.
+	// +kubebuilder:validation:Optional
+	SecretRef *xpv1.SecretReference `json:"secretRef,omitempty"`
+}
+
+// ConfigMapReference references a ConfigMap.
+type ConfigMapReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// Drift describes how the external resource observed in GoCD differs from
+// spec, e.g. after someone changed it in the GoCD UI.
+type Drift struct {
+	// Summary is a human readable description of the differences.
+	Summary string `json:"summary"`
+	// Fields lists the differing fields. Credentials are redacted.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	Fields []DriftField `json:"fields,omitempty"`
+	// DetectedAt is the time the drift was first detected.
+	// +optional
+	DetectedAt *metav1.Time `json:"detectedAt,omitempty"`
+}
+
+// DriftField is a field whose observed value differs from spec.
+type DriftField struct {
+	// Path of the field in the GoCD API representation, e.g.
+	// stages[build].jobs[compile].timeout.
+	Path string `json:"path"`
+	// Desired is the JSON encoded value in spec, empty if spec does not set it.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+	// Observed is the JSON encoded value in GoCD, empty if it is not set.
+	// +optional
+	Observed string `json:"observed,omitempty"`
+}
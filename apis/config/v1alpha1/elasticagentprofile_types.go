@@ -29,6 +29,10 @@ import (
 type ConfigProperty struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
+	// EncryptedValue is the value as encrypted by the GoCD server, for
+	// properties holding credentials. GoCD only reveals these encrypted.
+	// +kubebuilder:validation:Optional
+	EncryptedValue string `json:"encryptedValue,omitempty"`
 }
 
 type ElasticAgentProfileParameters struct {
@@ -48,12 +52,24 @@ type ElasticAgentProfileObservation struct {
 type ElasticAgentProfileSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       ElasticAgentProfileParameters `json:"forProvider"`
+
+	// IgnoreChanges lists fields of the GoCD representation of the elastic agent
+	// profile that are managed outside of this resource, as JSON pointer like
+	// paths, e.g. /properties/Image. Elements of arrays are addressed by their
+	// name, key or id, or by their index. Ignored fields are not compared and
+	// keep the value GoCD has when the elastic agent profile is updated.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^/`
+	IgnoreChanges []string `json:"ignoreChanges,omitempty"`
 }
 
 // A ElasticAgentProfileStatus represents the observed state of a ElasticAgentProfile.
 type ElasticAgentProfileStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          *runtime.RawExtension `json:"atProvider,omitempty"`
+	// Drift reports how the observed resource differs from spec.
+	// +optional
+	Drift *Drift `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -5,7 +5,6 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -342,6 +341,11 @@ type Job struct { //nolint:recvcheck
 	Timeout intstr.IntOrString `json:"timeout,omitempty"`
 	// EnvironmentVariables is a list of environment variables available to the job.
 	EnvironmentVariables []EnvironmentVariable `json:"environmentVariables"`
+	// EnvFrom imports the keys of Secrets and ConfigMaps as environment
+	// variables available to the job.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty"`
 	// Resources is a list of resources required by the job.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MaxItems=10
@@ -403,6 +407,11 @@ type Stage struct {
 	// EnvironmentVariables is a list of environment variables available to the stage.
 	// +kubebuilder:validation:MaxItems=50
 	EnvironmentVariables []EnvironmentVariable `json:"environmentVariables"`
+	// EnvFrom imports the keys of Secrets and ConfigMaps as environment
+	// variables available to the stage.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty"`
 	// Jobs is a list of jobs that are part of this stage.
 	// +kubebuilder:validation:MaxItems=50
 	Jobs []Job `json:"jobs"`
@@ -685,13 +694,21 @@ type Material struct { //nolint:recvcheck
 }
 
 // Parameter represents a key-value pair used for parameterizing GoCD pipeline configurations.
+// +kubebuilder:validation:XValidation:rule="has(self.value) != has(self.valueFrom)",message="exactly one of value and valueFrom must be set"
 type Parameter struct {
 	// Name is the identifier for the parameter.
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
-	// +kubebuilder:validation:Required
-	// Value is the value assigned to the parameter.
-	Value string `json:"value"`
+	// Value is the value assigned to the parameter. It may be empty.
+	// +kubebuilder:validation:Optional
+	Value *string `json:"value,omitempty"`
+	// ValueFrom reads the value of the parameter from a ConfigMap or Secret.
+	// Values read from a Secret are redacted in status.drift, events and dry
+	// run plans, but GoCD stores parameters in plain text and shows them to
+	// anyone who can view the pipeline; use a secure environment variable for
+	// credentials.
+	// +kubebuilder:validation:Optional
+	ValueFrom *ValueSource `json:"valueFrom,omitempty"`
 }
 
 // OriginType is a string type that specifies the origin of the pipeline configuration.
@@ -774,6 +791,11 @@ type PipelineConfigForProvider struct { //nolint:recvcheck
 	// +kubebuilder:validation:MaxItems=50
 	// +kubebuilder:validation:Optional
 	EnvironmentVariables []EnvironmentVariable `json:"environmentVariables,omitempty"`
+	// EnvFrom imports the keys of Secrets and ConfigMaps as environment
+	// variables for the pipeline.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=10
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty"`
 	// Materials is a list of materials (sources) for the pipeline.
 	// +kubebuilder:validation:MaxItems=50
 	// +kubebuilder:validation:MinItems=1
@@ -818,16 +840,77 @@ type Timer struct {
 type PipelineConfigSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       PipelineConfigForProvider `json:"forProvider"`
+
+	// IgnoreChanges lists fields of the GoCD representation of the pipeline
+	// that are managed outside of this resource, as JSON pointer like paths,
+	// e.g. /timer or /environment_variables/RELEASE. Elements of arrays are
+	// addressed by their name, key or id, or by their index. Ignored fields
+	// are not compared and keep the value GoCD has when the pipeline is
+	// updated.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^/`
+	IgnoreChanges []string `json:"ignoreChanges,omitempty"`
+}
+
+// MaterialObservation is a material of the observed pipeline.
+type MaterialObservation struct {
+	// Type of the material, e.g. git or dependency.
+	Type MaterialType `json:"type"`
+	// Name of the material, if it has one.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Fingerprint identifies the material by an HMAC-SHA256 of its type and
+	// source, e.g. the URL and branch of a git material, keyed with the hash
+	// keys of the provider. It changes when the current key is rotated.
+	Fingerprint string `json:"fingerprint"`
+}
+
+// StageObservation is a stage of the observed pipeline.
+type StageObservation struct {
+	// Name of the stage.
+	Name string `json:"name"`
+	// Jobs are the names of the jobs of the stage.
+	// +optional
+	Jobs []string `json:"jobs,omitempty"`
+}
+
+// PipelineConfigObservation is the observed state of a GoCD pipeline. It never
+// holds the values of secure variables or credentials.
+type PipelineConfigObservation struct {
+	// Name of the pipeline.
+	Name string `json:"name,omitempty"`
+	// Group the pipeline belongs to.
+	Group string `json:"group,omitempty"`
+	// LabelTemplate is the template used for pipeline labels.
+	LabelTemplate string `json:"labelTemplate,omitempty"`
+	// Template the pipeline is based on, if any.
+	Template string `json:"template,omitempty"`
+	// Origin is where the pipeline is defined.
+	Origin Origin `json:"origin,omitempty"`
+	// Materials of the pipeline.
+	Materials []MaterialObservation `json:"materials,omitempty"`
+	// Stages of the pipeline.
+	Stages []StageObservation `json:"stages,omitempty"`
+	// Links to the pipeline in the GoCD API.
+	Links EntityLinks `json:"links,omitempty"`
 }
 
 // A PipelineConfigStatus represents the observed state of a PipelineConfig.
 type PipelineConfigStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
-	AtProvider          *runtime.RawExtension `json:"atProvider,omitempty"`
-	// EnvironmentVariableHashes stores the hashes of the environment variables
+	AtProvider          PipelineConfigObservation `json:"atProvider,omitempty"`
+	// EnvironmentVariableHashes stored the hashes of the environment variables
 	// to detect changes in secure variables.
+	//
+	// Deprecated: Secure variables are compared through the encrypted values
+	// recorded in the gocd.crossplane.io/secure-variables annotation. Hashes
+	// found in this field are migrated to the annotation and the field is
+	// cleared.
 	// +optional
 	EnvironmentVariableHashes map[string]string `json:"environmentVariableHashes,omitempty"`
+	// Drift reports how the observed pipeline differs from spec.
+	// +optional
+	Drift *Drift `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -836,6 +919,8 @@ type PipelineConfigStatus struct {
 // +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
 // +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="GROUP",type="string",JSONPath=".status.atProvider.group"
+// +kubebuilder:printcolumn:name="ORIGIN",type="string",JSONPath=".status.atProvider.origin.type"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,gocd}
@@ -75,12 +75,24 @@ type RoleObservation struct {
 type RoleSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       RoleParameters `json:"forProvider"`
+
+	// IgnoreChanges lists fields of the GoCD representation of the role that are
+	// managed outside of this resource, as JSON pointer like paths, e.g.
+	// /attributes/users. Elements of arrays are addressed by their name, key or
+	// id, or by their index. Ignored fields are not compared and keep the value
+	// GoCD has when the role is updated.
+	// +optional
+	// +kubebuilder:validation:items:Pattern=`^/`
+	IgnoreChanges []string `json:"ignoreChanges,omitempty"`
 }
 
 // A RoleStatus represents the observed state of a role.
 type RoleStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          RoleObservation `json:"atProvider,omitempty"`
+	// Drift reports how the observed resource differs from spec.
+	// +optional
+	Drift *Drift `json:"drift,omitempty"`
 }
 
 // +kubebuilder:object:root=true
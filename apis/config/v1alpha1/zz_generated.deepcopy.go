@@ -118,6 +118,11 @@ func (in *AuthorizationConfigurationSpec) DeepCopyInto(out *AuthorizationConfigu
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.IgnoreChanges != nil {
+		in, out := &in.IgnoreChanges, &out.IgnoreChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationConfigurationSpec.
@@ -135,6 +140,11 @@ func (in *AuthorizationConfigurationStatus) DeepCopyInto(out *AuthorizationConfi
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(Drift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthorizationConfigurationStatus.
@@ -162,6 +172,21 @@ func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigProperty) DeepCopyInto(out *ConfigProperty) {
 	*out = *in
@@ -177,6 +202,45 @@ func (in *ConfigProperty) DeepCopy() *ConfigProperty {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Drift) DeepCopyInto(out *Drift) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]DriftField, len(*in))
+		copy(*out, *in)
+	}
+	if in.DetectedAt != nil {
+		in, out := &in.DetectedAt, &out.DetectedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Drift.
+func (in *Drift) DeepCopy() *Drift {
+	if in == nil {
+		return nil
+	}
+	out := new(Drift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftField) DeepCopyInto(out *DriftField) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftField.
+func (in *DriftField) DeepCopy() *DriftField {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticAgentProfile) DeepCopyInto(out *ElasticAgentProfile) {
 	*out = *in
@@ -276,6 +340,11 @@ func (in *ElasticAgentProfileSpec) DeepCopyInto(out *ElasticAgentProfileSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.IgnoreChanges != nil {
+		in, out := &in.IgnoreChanges, &out.IgnoreChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticAgentProfileSpec.
@@ -297,6 +366,11 @@ func (in *ElasticAgentProfileStatus) DeepCopyInto(out *ElasticAgentProfileStatus
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(Drift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticAgentProfileStatus.
@@ -327,6 +401,31 @@ func (in *EntityLinks) DeepCopy() *EntityLinks {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvFromSource) DeepCopyInto(out *EnvFromSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvFromSource.
+func (in *EnvFromSource) DeepCopy() *EnvFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EnvironmentVariable) DeepCopyInto(out *EnvironmentVariable) {
 	*out = *in
@@ -384,6 +483,13 @@ func (in *Job) DeepCopyInto(out *Job) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
 		*out = make([]string, len(*in))
@@ -681,6 +787,21 @@ func (in *MaterialAttributesTfs) DeepCopy() *MaterialAttributesTfs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaterialObservation) DeepCopyInto(out *MaterialObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaterialObservation.
+func (in *MaterialObservation) DeepCopy() *MaterialObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(MaterialObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Origin) DeepCopyInto(out *Origin) {
 	*out = *in
@@ -699,6 +820,16 @@ func (in *Origin) DeepCopy() *Origin {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Parameter) DeepCopyInto(out *Parameter) {
 	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
@@ -745,7 +876,9 @@ func (in *PipelineConfigForProvider) DeepCopyInto(out *PipelineConfigForProvider
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make([]Parameter, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.EnvironmentVariables != nil {
 		in, out := &in.EnvironmentVariables, &out.EnvironmentVariables
@@ -754,6 +887,13 @@ func (in *PipelineConfigForProvider) DeepCopyInto(out *PipelineConfigForProvider
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Materials != nil {
 		in, out := &in.Materials, &out.Materials
 		*out = make([]Material, len(*in))
@@ -814,11 +954,45 @@ func (in *PipelineConfigList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineConfigObservation) DeepCopyInto(out *PipelineConfigObservation) {
+	*out = *in
+	out.Origin = in.Origin
+	if in.Materials != nil {
+		in, out := &in.Materials, &out.Materials
+		*out = make([]MaterialObservation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]StageObservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Links = in.Links
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineConfigObservation.
+func (in *PipelineConfigObservation) DeepCopy() *PipelineConfigObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineConfigObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineConfigSpec) DeepCopyInto(out *PipelineConfigSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.IgnoreChanges != nil {
+		in, out := &in.IgnoreChanges, &out.IgnoreChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineConfigSpec.
@@ -835,11 +1009,7 @@ func (in *PipelineConfigSpec) DeepCopy() *PipelineConfigSpec {
 func (in *PipelineConfigStatus) DeepCopyInto(out *PipelineConfigStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	if in.AtProvider != nil {
-		in, out := &in.AtProvider, &out.AtProvider
-		*out = new(runtime.RawExtension)
-		(*in).DeepCopyInto(*out)
-	}
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 	if in.EnvironmentVariableHashes != nil {
 		in, out := &in.EnvironmentVariableHashes, &out.EnvironmentVariableHashes
 		*out = make(map[string]string, len(*in))
@@ -847,6 +1017,11 @@ func (in *PipelineConfigStatus) DeepCopyInto(out *PipelineConfigStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(Drift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineConfigStatus.
@@ -1006,6 +1181,11 @@ func (in *RoleSpec) DeepCopyInto(out *RoleSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
 	in.ForProvider.DeepCopyInto(&out.ForProvider)
+	if in.IgnoreChanges != nil {
+		in, out := &in.IgnoreChanges, &out.IgnoreChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleSpec.
@@ -1023,6 +1203,11 @@ func (in *RoleStatus) DeepCopyInto(out *RoleStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
 	in.AtProvider.DeepCopyInto(&out.AtProvider)
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(Drift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleStatus.
@@ -1046,6 +1231,13 @@ func (in *Stage) DeepCopyInto(out *Stage) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Jobs != nil {
 		in, out := &in.Jobs, &out.Jobs
 		*out = make([]Job, len(*in))
@@ -1106,6 +1298,26 @@ func (in *StageApprovalAuthorization) DeepCopy() *StageApprovalAuthorization {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StageObservation) DeepCopyInto(out *StageObservation) {
+	*out = *in
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StageObservation.
+func (in *StageObservation) DeepCopy() *StageObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(StageObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Task) DeepCopyInto(out *Task) {
 	*out = *in
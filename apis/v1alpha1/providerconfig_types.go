@@ -31,6 +31,70 @@ import (
 type ProviderConfigSpec struct {
   // Credentials required to authenticate to this provider.
   Credentials ProviderCredentials `json:"credentials"`
+
+  // RateLimit limits the requests the provider sends to the GoCD server of
+  // this ProviderConfig. It is shared by all managed resources using it.
+  // +optional
+  RateLimit *RateLimit `json:"rateLimit,omitempty"`
+
+  // ProxyURL is the proxy requests to the GoCD server are sent through, e.g.
+  // http://proxy.example.com:3128. When unset the HTTP_PROXY and HTTPS_PROXY
+  // environment variables of the provider apply.
+  // +kubebuilder:validation:Pattern=`^(http|https|socks5)://`
+  // +optional
+  ProxyURL string `json:"proxyURL,omitempty"`
+
+  // ProxyCredentialsSecretRef references a Secret with the username and
+  // password keys used to authenticate to the proxy at ProxyURL.
+  // +optional
+  ProxyCredentialsSecretRef *xpv1.SecretReference `json:"proxyCredentialsSecretRef,omitempty"`
+
+  // NoProxy lists the hosts reached without the proxy: host names, domains
+  // such as .example.com, IP addresses and CIDR ranges, optionally with a
+  // port. It extends the NO_PROXY environment variable of the provider,
+  // which applies whether or not ProxyURL is set.
+  // +optional
+  NoProxy []string `json:"noProxy,omitempty"`
+
+  // WireLog logs the requests the provider sends to the GoCD server and its
+  // responses, with credentials redacted. Requests are always logged when the
+  // provider runs with --debug.
+  // +optional
+  WireLog *WireLog `json:"wireLog,omitempty"`
+}
+
+// WireLog configures the logging of requests to a GoCD server.
+type WireLog struct {
+  // Enabled logs every request to the GoCD server and its response.
+  // +optional
+  Enabled bool `json:"enabled,omitempty"`
+
+  // MaxBodyBytes is the size request and response bodies are truncated at
+  // in the log. Defaults to 4096.
+  // +kubebuilder:validation:Minimum=0
+  // +optional
+  MaxBodyBytes int `json:"maxBodyBytes,omitempty"`
+}
+
+// RateLimit configures client-side rate limiting of requests to a GoCD server.
+type RateLimit struct {
+  // RequestsPerSecond is the sustained number of requests per second sent to
+  // the GoCD server. Zero disables rate limiting.
+  // +kubebuilder:validation:Minimum=0
+  // +optional
+  RequestsPerSecond int `json:"requestsPerSecond,omitempty"`
+
+  // Burst is the number of requests that may be sent at once above the
+  // sustained rate. Defaults to RequestsPerSecond.
+  // +kubebuilder:validation:Minimum=0
+  // +optional
+  Burst int `json:"burst,omitempty"`
+
+  // MaxInFlight is the maximum number of concurrent requests to the GoCD
+  // server. Zero means unlimited.
+  // +kubebuilder:validation:Minimum=0
+  // +optional
+  MaxInFlight int `json:"maxInFlight,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -45,12 +109,31 @@ type ProviderCredentials struct {
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
 type ProviderConfigStatus struct {
   xpv1.ProviderConfigStatus `json:",inline"`
+
+  // ServerVersion is the full version reported by the GoCD server.
+  // +optional
+  ServerVersion string `json:"serverVersion,omitempty"`
+
+  // User is the login name the provider is authenticated as.
+  // +optional
+  User string `json:"user,omitempty"`
+
+  // Admin indicates whether the authenticated user is a GoCD system administrator.
+  // +optional
+  Admin bool `json:"admin,omitempty"`
+
+  // LastHealthCheckTime is the last time the GoCD server was checked.
+  // +optional
+  LastHealthCheckTime *metav1.Time `json:"lastHealthCheckTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 
 // A ProviderConfig configures a GoCD provider.
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="HEALTHY",type="string",JSONPath=".status.conditions[?(@.type=='Healthy')].status"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".status.serverVersion"
+// +kubebuilder:printcolumn:name="USER",type="string",JSONPath=".status.user",priority=1
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentials.secretRef.name",priority=1
 // +kubebuilder:resource:scope=Cluster
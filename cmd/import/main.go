@@ -0,0 +1,102 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command import generates managed resource manifests from the objects of an
+// existing GoCD server so that the provider can adopt them.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/importer"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func main() {
+	var (
+		app = kingpin.New(filepath.Base(os.Args[0]), "Generate provider-gocd managed resources from an existing GoCD server.").DefaultEnvars()
+
+		credentials = app.Flag("credentials", "Path to a file holding the same JSON credentials as a ProviderConfig secret.").ExistingFile()
+		baseURL     = app.Flag("url", "Base URL of the GoCD server, e.g. https://gocd.example.com. Overrides the credentials file.").String()
+		username    = app.Flag("username", "GoCD user name. Overrides the credentials file.").String()
+		password    = app.Flag("password", "GoCD password. Overrides the credentials file.").String()
+		token       = app.Flag("token", "GoCD personal access token. Overrides the credentials file.").String()
+		insecure    = app.Flag("insecure", "Skip verification of the GoCD server's TLS certificate.").Default("false").Bool()
+
+		providerConfig  = app.Flag("provider-config", "Name of the ProviderConfig referenced by the generated resources.").Default("default").String()
+		secretNamespace = app.Flag("secret-namespace", "Namespace of the Secrets referenced for the values of secure variables.").Default("crossplane-system").String()
+		output          = app.Flag("output", "File the manifests are written to.").Short('o').Default("-").String()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	cfg := &v1alpha1.GocdProviderConfig{}
+	if *credentials != "" {
+		b, err := os.ReadFile(*credentials)
+		kingpin.FatalIfError(err, "Cannot read credentials file")
+		cfg, err = v1alpha1.ParseGocdProviderConfig(b)
+		kingpin.FatalIfError(err, "Cannot parse credentials file")
+	}
+	override(&cfg.BaseURL, *baseURL)
+	override(&cfg.Username, *username)
+	override(&cfg.Password, *password)
+	override(&cfg.Token, *token)
+	cfg.Insecure = cfg.Insecure || *insecure
+	if cfg.BaseURL == "" {
+		kingpin.Fatalf("The GoCD server URL is required, either with --url or in the credentials file")
+	}
+
+	gc, err := gocd.New(gocd.Config{
+		BaseURL:  cfg.BaseURL,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		Token:    cfg.Token,
+		Insecure: cfg.Insecure,
+	})
+	kingpin.FatalIfError(err, "Cannot create GoCD client")
+
+	res, err := importer.New(gc, importer.Options{
+		ProviderConfigName: *providerConfig,
+		SecretNamespace:    *secretNamespace,
+	}).Import(context.Background())
+	kingpin.FatalIfError(err, "Cannot import objects from %s", cfg.BaseURL)
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		kingpin.FatalIfError(err, "Cannot create output file")
+		defer f.Close() //nolint:errcheck
+		w = f
+	}
+	kingpin.FatalIfError(importer.Write(w, res.Manifests), "Cannot write manifests")
+
+	for _, s := range res.Skipped {
+		fmt.Fprintf(os.Stderr, "Skipped %s\n", s)
+	}
+	fmt.Fprintf(os.Stderr, "Generated %d manifests, skipped %d objects\n", len(res.Manifests), len(res.Skipped))
+}
+
+func override(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
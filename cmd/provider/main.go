@@ -47,9 +47,13 @@ import (
 
 	"github.com/marquesgui/provider-gocd/apis"
 	"github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/clients"
 	gocd "github.com/marquesgui/provider-gocd/internal/controller"
 	"github.com/marquesgui/provider-gocd/internal/features"
+	"github.com/marquesgui/provider-gocd/internal/keyring"
+	"github.com/marquesgui/provider-gocd/internal/tracing"
 	"github.com/marquesgui/provider-gocd/internal/version"
+	gocdclient "github.com/marquesgui/provider-gocd/pkg/gocd"
 )
 
 func main() {
@@ -64,11 +68,16 @@ func main() {
 
 		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
 
-		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
+		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config, and of the Secret holding the keys secret values are hashed with.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
 		enableManagementPolicies   = app.Flag("enable-management-policies", "Enable support for Management Policies.").Default("false").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
 		enableChangeLogs           = app.Flag("enable-changelogs", "Enable support for capturing change logs during reconciliation.").Default("false").Envar("ENABLE_CHANGE_LOGS").Bool()
 		changelogsSocketPath       = app.Flag("changelogs-socket-path", "Path for changelogs socket (if enabled)").Default("/var/run/changelogs/changelogs.sock").Envar("CHANGELOGS_SOCKET_PATH").String()
+		dryRun                     = app.Flag("dry-run", "Observe resources and report the changes that would be made to GoCD without making them. The gocd.crossplane.io/dry-run annotation overrides this per resource.").Default("false").Envar("DRY_RUN").Bool()
+
+		enableTracing   = app.Flag("enable-tracing", "Export OpenTelemetry traces of reconciles and GoCD API calls over OTLP.").Default("false").Envar("ENABLE_TRACING").Bool()
+		tracingEndpoint = app.Flag("tracing-endpoint", "OTLP gRPC endpoint traces are exported to. Defaults to the OTEL_EXPORTER_OTLP_* environment variables.").Envar("TRACING_ENDPOINT").String()
+		tracingInsecure = app.Flag("tracing-insecure", "Connect to the OTLP endpoint without TLS.").Default("false").Envar("TRACING_INSECURE").Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -85,6 +94,22 @@ func main() {
 		ctrl.SetLogger(zap.New(zap.WriteTo(io.Discard)))
 	}
 
+	if *enableTracing {
+		shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+			Endpoint:    *tracingEndpoint,
+			Insecure:    *tracingInsecure,
+			ServiceName: "provider-gocd",
+			Version:     version.Version,
+		})
+		kingpin.FatalIfError(err, "Cannot set up tracing")
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				log.Info("Cannot flush traces", "error", err)
+			}
+		}()
+		log.Info("Tracing enabled", "endpoint", *tracingEndpoint)
+	}
+
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
 
@@ -116,6 +141,7 @@ func main() {
 
 	metrics.Registry.MustRegister(metricRecorder)
 	metrics.Registry.MustRegister(stateMetrics)
+	metrics.Registry.MustRegister(gocdclient.Collectors()...)
 
 	o := controller.Options{
 		Logger:                  log,
@@ -154,6 +180,11 @@ func main() {
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaManagementPolicies)
 	}
 
+	if *dryRun {
+		o.Features.Enable(features.EnableDryRun)
+		log.Info("Dry run enabled: no changes are made to GoCD", "flag", features.EnableDryRun)
+	}
+
 	if *enableChangeLogs {
 		o.Features.Enable(feature.EnableAlphaChangeLogs)
 		log.Info("Alpha feature enabled", "flag", feature.EnableAlphaChangeLogs)
@@ -169,6 +200,9 @@ func main() {
 		o.ChangeLogOptions = &clo
 	}
 
+	clients.DefaultCache = clients.NewCache(clients.NewClientFn(log.WithValues("component", "gocd-client"), *debug))
+	keyring.DefaultSource = keyring.NewSource(*namespace)
+
 	kingpin.FatalIfError(gocd.Setup(mgr, o), "Cannot setup GoCD controllers")
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
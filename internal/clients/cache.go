@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	apisv1alpha1 "github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/utils"
+)
+
+// DefaultCache is the client cache shared by all controllers of the provider.
+// The provider replaces it before setting up controllers to configure the
+// clients it builds.
+var DefaultCache = NewCache(NewClient)
+
+type idleCloser interface {
+	CloseIdleConnections()
+}
+
+type cachedClient struct {
+	name   string
+	hash   string
+	client gocd.Client
+}
+
+// A Cache keeps one GoCD client per ProviderConfig so that connections are
+// reused across reconciles. A client is rebuilt whenever the credentials or
+// the spec of its ProviderConfig change.
+type Cache struct {
+	mu          sync.Mutex
+	clients     map[types.UID]cachedClient
+	newClientFn func(pc *apisv1alpha1.ProviderConfig, creds Credentials) (gocd.Client, error)
+}
+
+// NewCache returns an empty Cache that builds clients with the supplied function.
+func NewCache(fn func(pc *apisv1alpha1.ProviderConfig, creds Credentials) (gocd.Client, error)) *Cache {
+	return &Cache{
+		clients:     map[types.UID]cachedClient{},
+		newClientFn: fn,
+	}
+}
+
+// Get returns the client for the supplied ProviderConfig and credentials,
+// building a new one if none is cached or the cached one is stale.
+func (c *Cache) Get(pc *apisv1alpha1.ProviderConfig, creds Credentials) (gocd.Client, error) {
+	spec, err := json.Marshal(pc.Spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot marshal ProviderConfig spec")
+	}
+	hash := utils.ToSha256(strings.Join([]string{string(spec), string(creds.GoCD), creds.ProxyUsername, creds.ProxyPassword}, "\x00"))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cur, ok := c.clients[pc.GetUID()]
+	if ok && cur.hash == hash {
+		return cur.client, nil
+	}
+
+	gc, err := c.newClientFn(pc, creds)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		closeIdle(cur.client)
+	}
+	c.clients[pc.GetUID()] = cachedClient{name: pc.GetName(), hash: hash, client: gc}
+	return gc, nil
+}
+
+// Evict discards the client of the named ProviderConfig, if any.
+func (c *Cache) Evict(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for uid, cur := range c.clients {
+		if cur.name == name {
+			closeIdle(cur.client)
+			delete(c.clients, uid)
+		}
+	}
+}
+
+// Len returns the number of cached clients.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.clients)
+}
+
+func closeIdle(gc gocd.Client) {
+	if ic, ok := gc.(idleCloser); ok {
+		ic.CloseIdleConnections()
+	}
+}
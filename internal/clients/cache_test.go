@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func TestCache(t *testing.T) {
+	built := 0
+	c := NewCache(func(pc *apisv1alpha1.ProviderConfig, creds Credentials) (gocd.Client, error) {
+		built++
+		return NewClient(pc, creds)
+	})
+
+	pc := &apisv1alpha1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "uid-1"}}
+	creds := Credentials{GoCD: []byte(`{"baseURL": "https://gocd.example.com", "token": "a"}`)}
+
+	first, err := c.Get(pc, creds)
+	if err != nil {
+		t.Fatalf("Get(...): unexpected error: %v", err)
+	}
+	second, _ := c.Get(pc, creds)
+	if first != second || built != 1 {
+		t.Errorf("Get(...): expected the cached client to be reused, built %d clients", built)
+	}
+
+	rotatedCreds := []byte(`{"baseURL": "https://gocd.example.com", "token": "b"}`)
+	rotated, _ := c.Get(pc, Credentials{GoCD: rotatedCreds})
+	if rotated == first || built != 2 {
+		t.Errorf("Get(...): expected a new client after the credentials changed, built %d clients", built)
+	}
+	proxied, _ := c.Get(pc, Credentials{GoCD: rotatedCreds, ProxyUsername: "egress", ProxyPassword: "s3cr3t"})
+	if proxied == rotated || built != 3 {
+		t.Errorf("Get(...): expected a new client after the proxy credentials changed, built %d clients", built)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len(): want 1, got %d", c.Len())
+	}
+
+	other := &apisv1alpha1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "uid-2"}}
+	if _, err := c.Get(other, creds); err != nil {
+		t.Fatalf("Get(...): unexpected error: %v", err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len(): want 2, got %d", c.Len())
+	}
+
+	c.Evict("default")
+	if c.Len() != 1 {
+		t.Errorf("Evict(...): want 1 cached client, got %d", c.Len())
+	}
+
+	if _, err := c.Get(pc, Credentials{GoCD: []byte(`{}`)}); err == nil {
+		t.Errorf("Get(...): expected an error for credentials without a base URL")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Package clients
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clients
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+const (
+	errGetProxySecret = "cannot get proxy credentials secret"
+
+	keyProxyUsername = "username"
+	keyProxyPassword = "password"
+)
+
+// Credentials of a ProviderConfig.
+type Credentials struct {
+	// GoCD is the GoCD provider config document, with the base URL and the
+	// credentials of the GoCD server.
+	GoCD []byte
+
+	// ProxyUsername and ProxyPassword authenticate to the proxy.
+	ProxyUsername string
+	ProxyPassword string
+}
+
+// ExtractCredentials reads the credentials of the supplied ProviderConfig.
+func ExtractCredentials(ctx context.Context, kube client.Client, pc *apisv1alpha1.ProviderConfig) (Credentials, error) {
+	cd := pc.Spec.Credentials
+	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return Credentials{}, err
+	}
+	creds := Credentials{GoCD: data}
+
+	ref := pc.Spec.ProxyCredentialsSecretRef
+	if ref == nil {
+		return creds, nil
+	}
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return Credentials{}, errors.Wrap(err, errGetProxySecret)
+	}
+	creds.ProxyUsername = string(s.Data[keyProxyUsername])
+	creds.ProxyPassword = string(s.Data[keyProxyPassword])
+	return creds, nil
+}
+
+// NewClient builds a GoCD API client from a ProviderConfig and its
+// credentials.
+func NewClient(pc *apisv1alpha1.ProviderConfig, creds Credentials) (gocd.Client, error) {
+	return newClient(pc, creds, nil)
+}
+
+// NewClientFn returns a function building clients like NewClient that log
+// their requests and responses to log: all of them when debug is set, and
+// otherwise those of ProviderConfigs with spec.wireLog enabled.
+func NewClientFn(log logging.Logger, debug bool) func(*apisv1alpha1.ProviderConfig, Credentials) (gocd.Client, error) {
+	return func(pc *apisv1alpha1.ProviderConfig, creds Credentials) (gocd.Client, error) {
+		if !debug && (pc.Spec.WireLog == nil || !pc.Spec.WireLog.Enabled) {
+			return newClient(pc, creds, nil)
+		}
+		return newClient(pc, creds, log)
+	}
+}
+
+func newClient(pc *apisv1alpha1.ProviderConfig, creds Credentials, log logging.Logger) (gocd.Client, error) {
+	spec := pc.Spec
+	cfg, err := apisv1alpha1.ParseGocdProviderConfig(creds.GoCD)
+	if err != nil {
+		return nil, err
+	}
+	gc := gocd.Config{
+		Name:          pc.GetName(),
+		BaseURL:       cfg.BaseURL,
+		Username:      cfg.Username,
+		Password:      cfg.Password,
+		Token:         cfg.Token,
+		Insecure:      cfg.Insecure,
+		ProxyURL:      spec.ProxyURL,
+		ProxyUsername: creds.ProxyUsername,
+		ProxyPassword: creds.ProxyPassword,
+		NoProxy:       spec.NoProxy,
+		Logger:        log,
+	}
+	if spec.WireLog != nil {
+		gc.MaxLogBodyBytes = spec.WireLog.MaxBodyBytes
+	}
+	if rl := spec.RateLimit; rl != nil {
+		gc.RequestsPerSecond = float64(rl.RequestsPerSecond)
+		gc.Burst = rl.Burst
+		gc.MaxInFlight = rl.MaxInFlight
+	}
+	return gocd.New(gc)
+}
+
+// CheckHealth returns an error if the last health check of the supplied
+// ProviderConfig failed. A ProviderConfig that has not been checked yet is
+// considered healthy so managed resources are not blocked on the first check.
+func CheckHealth(pc *apisv1alpha1.ProviderConfig) error {
+	c := pc.Status.GetCondition(xpv1.TypeHealthy)
+	if c.Status != corev1.ConditionFalse {
+		return nil
+	}
+	return errors.Errorf("ProviderConfig %q is unhealthy: %s", pc.GetName(), c.Message)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apisv1alpha1 "github.com/marquesgui/provider-gocd/apis/v1alpha1"
+)
+
+func TestExtractCredentials(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = corev1.AddToScheme(s)
+
+	creds := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "crossplane-system"},
+		Data:       map[string][]byte{"credentials": []byte(`{"baseURL": "http://gocd.example.com"}`)},
+	}
+	proxy := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: "crossplane-system"},
+		Data:       map[string][]byte{"username": []byte("egress"), "password": []byte("s3cr3t")},
+	}
+	spec := func(ref *xpv1.SecretReference) apisv1alpha1.ProviderConfigSpec {
+		return apisv1alpha1.ProviderConfigSpec{
+			Credentials: apisv1alpha1.ProviderCredentials{
+				Source: xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: "creds", Namespace: "crossplane-system"},
+					Key:             "credentials",
+				}},
+			},
+			ProxyURL:                  "http://proxy.example.com:3128",
+			ProxyCredentialsSecretRef: ref,
+		}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		spec    apisv1alpha1.ProviderConfigSpec
+		want    Credentials
+		wantErr bool
+	}{
+		"NoProxyCredentials": {
+			reason: "Only the GoCD credentials should be read without a proxy credentials secret.",
+			spec:   spec(nil),
+			want:   Credentials{GoCD: creds.Data["credentials"]},
+		},
+		"ProxyCredentials": {
+			reason: "The proxy username and password should be read from the referenced secret.",
+			spec:   spec(&xpv1.SecretReference{Name: "proxy", Namespace: "crossplane-system"}),
+			want:   Credentials{GoCD: creds.Data["credentials"], ProxyUsername: "egress", ProxyPassword: "s3cr3t"},
+		},
+		"MissingProxySecret": {
+			reason:  "A missing proxy credentials secret should be an error.",
+			spec:    spec(&xpv1.SecretReference{Name: "missing", Namespace: "crossplane-system"}),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := fake.NewClientBuilder().WithScheme(s).WithObjects(creds, proxy).Build()
+			pc := &apisv1alpha1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Spec: tc.spec}
+
+			got, err := ExtractCredentials(context.Background(), kube, pc)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nExtractCredentials(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nExtractCredentials(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
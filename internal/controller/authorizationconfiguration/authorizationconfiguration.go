@@ -20,7 +20,6 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"maps"
 
@@ -42,8 +41,14 @@ import (
 
 	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
 	apisv1alpha1 "github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/clients"
 	"github.com/marquesgui/provider-gocd/internal/controller/helper"
+	"github.com/marquesgui/provider-gocd/internal/drift"
+	"github.com/marquesgui/provider-gocd/internal/dryrun"
 	"github.com/marquesgui/provider-gocd/internal/features"
+	"github.com/marquesgui/provider-gocd/internal/ignore"
+	"github.com/marquesgui/provider-gocd/internal/lateinit"
+	"github.com/marquesgui/provider-gocd/internal/tracing"
 	"github.com/marquesgui/provider-gocd/pkg/gocd"
 )
 
@@ -64,29 +69,9 @@ type gocdAuthzService interface {
 	Delete(ctx context.Context, id string, etag string) error
 }
 
-// newServiceFn builds the real GoCD AuthorizationConfigurations service from credentials bytes.
-var newServiceFn = func(creds []byte) (any, error) {
-	// Credentials expected as JSON. We'll parse minimally.
-	type c struct {
-		BaseURL  string `json:"baseURL"`
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Token    string `json:"token"`
-		Insecure bool   `json:"insecure"`
-	}
-	var cfg c
-	_ = json.Unmarshal(creds, &cfg)
-	gc, err := gocd.New(gocd.Config{
-		BaseURL:  cfg.BaseURL,
-		Username: cfg.Username,
-		Password: cfg.Password,
-		Token:    cfg.Token,
-		Insecure: cfg.Insecure,
-	})
-	if err != nil {
-		return nil, err
-	}
-	return gc.AuthorizationConfigurations(), nil
+// newServiceFn returns the GoCD service this controller manages.
+var newServiceFn = func(gc gocd.Client) any {
+	return gc.AuthorizationConfigurations()
 }
 
 // Setup adds a controller that reconciles AuthorizationConfiguration managed resources.
@@ -98,15 +83,20 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	dryRun := o.Features.Enabled(features.EnableDryRun)
 	opts := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(tracing.NewConnector(v1alpha1.AuthorizationConfigurationKind, dryrun.NewConnector(&connector{
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients:      clients.DefaultCache,
 			newServiceFn: newServiceFn,
-		}),
+			drift:        drift.NewReporter(recorder),
+			dryRun:       dryRun,
+		}, dryRun, recorder))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...),
 		managed.WithManagementPolicies(),
 	}
@@ -143,7 +133,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds []byte) (any, error)
+	clients      *clients.Cache
+	newServiceFn func(gc gocd.Client) any
+	drift        *drift.Reporter
+	dryRun       bool
 }
 
 // Connect typically produces an ExternalClient by:
@@ -166,27 +159,39 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err := clients.CheckHealth(pc); err != nil {
+		return nil, err
+	}
+
+	creds, err := clients.ExtractCredentials(ctx, c.kube, pc)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(data)
+	gc, err := c.clients.Get(pc, creds)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
+
+	svc := c.newServiceFn(gc)
 	as, ok := svc.(gocdAuthzService)
 	if !ok {
 		return nil, errors.New("returned service does not implement gocdAuthzService")
 	}
-	return &external{service: as}, nil
+	return &external{service: as, drift: c.drift, dryRun: dryrun.Enabled(mg, c.dryRun)}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
 	service gocdAuthzService
+	drift   *drift.Reporter
+	// Whether the managed resource is in dry run, in which case Observe does
+	// not change it.
+	dryRun bool
+	// The authorization configuration as observed during this reconcile,
+	// which ignored fields are filled from when it is updated.
+	observed *gocd.AuthorizationConfiguration
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -207,12 +212,32 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if got == nil {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+	c.observed = got
 
 	// Update status from observed state
 	updateStatus(cr, got)
 	// Store ETag for future updates
 	helper.KeepETag(cr, etag)
-	upToDate := isUpToDate(cr, got, etag)
+	// A dry run late initializes a copy, which the desired authorization
+	// configuration is built from but which is never persisted.
+	target := cr
+	if c.dryRun {
+		target = cr.DeepCopy()
+	}
+	lateInitialized := lateInitialize(&target.Spec.ForProvider, got)
+	desired := createAuthzRequest(id, target)
+	if err := ignore.Apply(&desired, got, cr.Spec.IgnoreChanges); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot ignore changes")
+	}
+	upToDate := isUpToDate(cr, desired, got, etag)
+
+	var diffs []drift.Difference
+	if !upToDate {
+		if diffs, err = drift.Diff(desired, got); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot compare authorization configuration")
+		}
+	}
+	c.drift.Report(cr, &cr.Status.Drift, diffs)
 
 	if upToDate {
 		cr.SetConditions(xpv1.Available())
@@ -221,9 +246,10 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: lateInitialized && !c.dryRun,
+		ConnectionDetails:       managed.ConnectionDetails{},
 	}, nil
 }
 
@@ -235,14 +261,7 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	id := helper.GetID(cr, cr.Spec.ForProvider.ID)
 
-	in := gocd.AuthorizationConfiguration{
-		ID:                         id,
-		PluginID:                   cr.Spec.ForProvider.PluginID,
-		AllowOnlyKnownUsersToLogin: cr.Spec.ForProvider.AllowOnlyKnowUsersToLogin,
-	}
-	for _, p := range cr.Spec.ForProvider.Properties {
-		in.Properties = append(in.Properties, gocd.ConfigProperty{Key: p.Key, Value: p.Value})
-	}
+	in := createAuthzRequest(id, cr)
 
 	out, etag, err := c.service.Create(ctx, in)
 	if err != nil {
@@ -264,13 +283,9 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	id := meta.GetExternalName(cr)
-	in := gocd.AuthorizationConfiguration{
-		ID:                         id,
-		PluginID:                   cr.Spec.ForProvider.PluginID,
-		AllowOnlyKnownUsersToLogin: cr.Spec.ForProvider.AllowOnlyKnowUsersToLogin,
-	}
-	for _, p := range cr.Spec.ForProvider.Properties {
-		in.Properties = append(in.Properties, gocd.ConfigProperty{Key: p.Key, Value: p.Value})
+	in := createAuthzRequest(id, cr)
+	if err := ignore.Apply(&in, c.observed, cr.Spec.IgnoreChanges); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot ignore changes")
 	}
 
 	etag := helper.GetETag(cr)
@@ -308,6 +323,14 @@ func (c *external) Disconnect(ctx context.Context) error {
 	return nil
 }
 
+// lateInitialize fills the plugin ID of spec from the observed authorization
+// configuration. The ID is left to the external name. Properties are never
+// late initialized, so that removing one from spec removes it from GoCD. It
+// reports whether spec was changed.
+func lateInitialize(spec *v1alpha1.AuthorizationConfigurationParameters, got *gocd.AuthorizationConfiguration) bool {
+	return lateinit.Value(&spec.PluginID, got.PluginID)
+}
+
 func updateStatus(cr *v1alpha1.AuthorizationConfiguration, got *gocd.AuthorizationConfiguration) {
 	cr.Status.AtProvider.ID = got.ID
 	cr.Status.AtProvider.PluginID = got.PluginID
@@ -333,8 +356,8 @@ func updateStatus(cr *v1alpha1.AuthorizationConfiguration, got *gocd.Authorizati
 	}
 }
 
-func isUpToDate(cr *v1alpha1.AuthorizationConfiguration, got *gocd.AuthorizationConfiguration, etag string) bool {
-	hasSameProperties := func(a []v1alpha1.KeyValue, b []gocd.ConfigProperty) bool {
+func isUpToDate(cr *v1alpha1.AuthorizationConfiguration, desired gocd.AuthorizationConfiguration, got *gocd.AuthorizationConfiguration, etag string) bool {
+	hasSameProperties := func(a []gocd.ConfigProperty, b []gocd.ConfigProperty) bool {
 		mapA := make(map[string]string)
 		for _, v := range a {
 			if v.Key != "ClientSecret" {
@@ -348,14 +371,26 @@ func isUpToDate(cr *v1alpha1.AuthorizationConfiguration, got *gocd.Authorization
 			}
 		}
 		return maps.Equal(mapA, mapB)
-	}(cr.Spec.ForProvider.Properties, got.Properties)
+	}(desired.Properties, got.Properties)
 
-	return cr.Spec.ForProvider.PluginID == got.PluginID &&
-		cr.Spec.ForProvider.AllowOnlyKnowUsersToLogin == got.AllowOnlyKnownUsersToLogin &&
+	return desired.PluginID == got.PluginID &&
+		desired.AllowOnlyKnownUsersToLogin == got.AllowOnlyKnownUsersToLogin &&
 		cr.Status.AtProvider.TransactionID == createTransactionID(etag, cr.Generation) &&
 		hasSameProperties
 }
 
+func createAuthzRequest(id string, cr *v1alpha1.AuthorizationConfiguration) gocd.AuthorizationConfiguration {
+	in := gocd.AuthorizationConfiguration{
+		ID:                         id,
+		PluginID:                   cr.Spec.ForProvider.PluginID,
+		AllowOnlyKnownUsersToLogin: cr.Spec.ForProvider.AllowOnlyKnowUsersToLogin,
+	}
+	for _, p := range cr.Spec.ForProvider.Properties {
+		in.Properties = append(in.Properties, gocd.ConfigProperty{Key: p.Key, Value: p.Value, EncryptedValue: p.EncryptedValue})
+	}
+	return in
+}
+
 func createTransactionID(etag string, generation int64) string {
 	key := fmt.Sprintf("%s-%d", etag, generation)
 	sum := sha256.Sum256([]byte(key))
@@ -35,6 +35,7 @@ import (
 func TestObserve(t *testing.T) {
 	type fields struct {
 		service gocdAuthzService
+		dryRun  bool
 	}
 
 	type args struct {
@@ -84,6 +85,29 @@ func TestObserve(t *testing.T) {
 				},
 			},
 		},
+		"DryRun": {
+			reason: "Should not late initialize the spec in dry run",
+			fields: fields{
+				service: m,
+				dryRun:  true,
+			},
+			args: args{
+				ctx: context.Background(),
+				mg: func() resource.Managed {
+					cr := &v1alpha1.AuthorizationConfiguration{}
+					meta.SetExternalName(cr, id)
+					cr.Spec.ForProvider.AllowOnlyKnowUsersToLogin = true
+					cr.Spec.ForProvider.Properties = []v1alpha1.KeyValue{{Key: "k", Value: "v"}}
+					return cr
+				}(),
+			},
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ConnectionDetails: managed.ConnectionDetails{},
+				},
+			},
+		},
 		"NotFound": {
 			reason: "Should return ResourceExists: false when GoCD returns 404",
 			fields: fields{
@@ -124,7 +148,7 @@ func TestObserve(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			if name == "Exists" {
+			if name == "Exists" || name == "DryRun" {
 				m.EXPECT().Get(gomock.Any(), id).Return(&gocd.AuthorizationConfiguration{
 					ID:                         id,
 					PluginID:                   "plugin",
@@ -139,7 +163,7 @@ func TestObserve(t *testing.T) {
 				m.EXPECT().Get(gomock.Any(), id).Return(nil, "", errors.New("some error"))
 			}
 
-			e := external{service: tc.fields.service}
+			e := external{service: tc.fields.service, dryRun: tc.fields.dryRun}
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -147,6 +171,9 @@ func TestObserve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.o, got); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
 			}
+			if cr, ok := tc.args.mg.(*v1alpha1.AuthorizationConfiguration); ok && tc.fields.dryRun && cr.Spec.ForProvider.PluginID != "" {
+				t.Errorf("\n%s\ne.Observe(...): want spec unchanged, got plugin ID %q", tc.reason, cr.Spec.ForProvider.PluginID)
+			}
 		})
 	}
 }
@@ -419,3 +446,31 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestLateInitialize(t *testing.T) {
+	got := &gocd.AuthorizationConfiguration{
+		ID:       "ldap",
+		PluginID: "cd.go.authentication.ldap",
+		Properties: []gocd.ConfigProperty{
+			{Key: "Url", Value: "ldap://ldap.example.com"},
+			{Key: "SearchBase", Value: "ou=users"},
+		},
+	}
+	spec := v1alpha1.AuthorizationConfigurationParameters{
+		Properties: []v1alpha1.KeyValue{{Key: "Url", Value: "ldap://ldap.example.com"}},
+	}
+	want := v1alpha1.AuthorizationConfigurationParameters{
+		PluginID:   "cd.go.authentication.ldap",
+		Properties: []v1alpha1.KeyValue{{Key: "Url", Value: "ldap://ldap.example.com"}},
+	}
+
+	if !lateInitialize(&spec, got) {
+		t.Errorf("lateInitialize(...): want spec changed")
+	}
+	if diff := cmp.Diff(want, spec); diff != "" {
+		t.Errorf("lateInitialize(...): want the removed SearchBase property to stay removed, -want, +got:\n%s", diff)
+	}
+	if lateInitialize(&spec, got) {
+		t.Errorf("lateInitialize(...): want a late initialized spec to converge")
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/clients"
+)
+
+const (
+	healthCheckTimeout = 30 * time.Second
+
+	errGetPC           = "cannot get ProviderConfig"
+	errGetCreds        = "cannot get credentials"
+	errNewClient       = "cannot create GoCD client"
+	errGetVersion      = "cannot get GoCD server version"
+	errGetCurrentUser  = "cannot authenticate against GoCD"
+	errGetAdmin        = "cannot determine whether the GoCD user is an admin"
+	errUpdateStatus    = "cannot update ProviderConfig status"
+	reasonHealthy      = xpv1.ConditionReason("HealthCheckSucceeded")
+	reasonHealthFailed = xpv1.ConditionReason("HealthCheckFailed")
+)
+
+// Healthy indicates that the GoCD server of a ProviderConfig is reachable and
+// accepts its credentials.
+func Healthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonHealthy,
+	}
+}
+
+// Unhealthy indicates that the GoCD server of a ProviderConfig could not be
+// reached or rejected its credentials.
+func Unhealthy(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reasonHealthFailed,
+		Message:            err.Error(),
+	}
+}
+
+// SetupHealth adds a controller that periodically checks that the GoCD server
+// of each ProviderConfig is reachable and accepts its credentials.
+func SetupHealth(mgr ctrl.Manager, o controller.Options) error {
+	name := "health/" + strings.ToLower(v1alpha1.ProviderConfigGroupKind)
+
+	r := &healthReconciler{
+		kube:     mgr.GetClient(),
+		log:      o.Logger.WithValues("controller", name),
+		interval: o.PollInterval,
+		clients:  clients.DefaultCache,
+	}
+
+	// Only spec changes trigger a check; periodic checks are driven by
+	// RequeueAfter so our own status updates don't cause a hot loop.
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ProviderConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+type healthReconciler struct {
+	kube     client.Client
+	log      logging.Logger
+	interval time.Duration
+	clients  *clients.Cache
+}
+
+// Reconcile checks the health of a ProviderConfig and records the result in
+// its status.
+func (r *healthReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	pc := &v1alpha1.ProviderConfig{}
+	if err := r.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.clients.Evict(req.Name)
+		}
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPC)
+	}
+	if meta.WasDeleted(pc) {
+		r.clients.Evict(pc.GetName())
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.check(ctx, pc); err != nil {
+		log.Debug("ProviderConfig is unhealthy", "error", err)
+		pc.Status.SetConditions(Unhealthy(err), xpv1.Unavailable().WithMessage(err.Error()))
+	} else {
+		pc.Status.SetConditions(Healthy(), xpv1.Available())
+	}
+	now := metav1.Now()
+	pc.Status.LastHealthCheckTime = &now
+
+	if err := r.kube.Status().Update(ctx, pc); err != nil {
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	return reconcile.Result{RequeueAfter: r.interval}, nil
+}
+
+func (r *healthReconciler) check(ctx context.Context, pc *v1alpha1.ProviderConfig) error {
+	// Don't report an identity from a previous check if this one fails.
+	pc.Status.User, pc.Status.Admin = "", false
+
+	creds, err := clients.ExtractCredentials(ctx, r.kube, pc)
+	if err != nil {
+		return errors.Wrap(err, errGetCreds)
+	}
+	gc, err := r.clients.Get(pc, creds)
+	if err != nil {
+		return errors.Wrap(err, errNewClient)
+	}
+
+	v, err := gc.Server().Version(ctx)
+	if err != nil {
+		return errors.Wrap(err, errGetVersion)
+	}
+	pc.Status.ServerVersion = v.FullVersion
+
+	u, err := gc.Server().CurrentUser(ctx)
+	if err != nil {
+		return errors.Wrap(err, errGetCurrentUser)
+	}
+	pc.Status.User = u.LoginName
+
+	admin, err := gc.Server().IsAdmin(ctx)
+	if err != nil {
+		return errors.Wrap(err, errGetAdmin)
+	}
+	pc.Status.Admin = admin
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/clients"
+)
+
+func TestHealthReconcile(t *testing.T) {
+	type want struct {
+		result  reconcile.Result
+		healthy corev1.ConditionStatus
+		ready   corev1.ConditionStatus
+		version string
+		user    string
+		admin   bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		want    want
+	}{
+		"Healthy": {
+			reason: "A reachable server that accepts the credentials should be reported as healthy.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/go/api/version":
+					fmt.Fprintln(w, `{"version": "25.3.0", "full_version": "25.3.0 (20935-abc)"}`)
+				case "/go/api/current_user":
+					fmt.Fprintln(w, `{"login_name": "bot"}`)
+				default:
+					w.WriteHeader(http.StatusForbidden)
+				}
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: time.Minute},
+				healthy: corev1.ConditionTrue,
+				ready:   corev1.ConditionTrue,
+				version: "25.3.0 (20935-abc)",
+				user:    "bot",
+			},
+		},
+		"Unauthorized": {
+			reason: "A server that rejects the credentials should be reported as unhealthy.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/go/api/version" {
+					fmt.Fprintln(w, `{"version": "25.3.0", "full_version": "25.3.0 (20935-abc)"}`)
+					return
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: time.Minute},
+				healthy: corev1.ConditionFalse,
+				ready:   corev1.ConditionFalse,
+				version: "25.3.0 (20935-abc)",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(tc.handler)
+			defer ts.Close()
+
+			s := runtime.NewScheme()
+			_ = corev1.AddToScheme(s)
+			_ = v1alpha1.SchemeBuilder.AddToScheme(s)
+
+			pc := &v1alpha1.ProviderConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "default"},
+				Spec: v1alpha1.ProviderConfigSpec{Credentials: v1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceSecret,
+					CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "creds", Namespace: "crossplane-system"},
+						Key:             "credentials",
+					}},
+				}},
+			}
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "crossplane-system"},
+				Data: map[string][]byte{
+					"credentials": []byte(fmt.Sprintf(`{"baseURL": %q, "username": "bot", "password": "secret"}`, ts.URL)),
+				},
+			}
+			kube := fake.NewClientBuilder().WithScheme(s).WithObjects(pc, secret).WithStatusSubresource(pc).Build()
+
+			r := &healthReconciler{kube: kube, log: logging.NewNopLogger(), interval: time.Minute, clients: clients.NewCache(clients.NewClient)}
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "default"}})
+			if err != nil {
+				t.Fatalf("\n%s\nReconcile(...): unexpected error: %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			out := &v1alpha1.ProviderConfig{}
+			if err := kube.Get(context.Background(), types.NamespacedName{Name: "default"}, out); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.healthy, out.Status.GetCondition(xpv1.TypeHealthy).Status); diff != "" {
+				t.Errorf("\n%s\nHealthy condition: -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ready, out.Status.GetCondition(xpv1.TypeReady).Status); diff != "" {
+				t.Errorf("\n%s\nReady condition: -want, +got:\n%s", tc.reason, diff)
+			}
+			if out.Status.ServerVersion != tc.want.version || out.Status.User != tc.want.user || out.Status.Admin != tc.want.admin {
+				t.Errorf("\n%s\nstatus: got version=%q user=%q admin=%v", tc.reason, out.Status.ServerVersion, out.Status.User, out.Status.Admin)
+			}
+			if out.Status.LastHealthCheckTime == nil {
+				t.Errorf("\n%s\nLastHealthCheckTime was not set", tc.reason)
+			}
+		})
+	}
+}
@@ -32,8 +32,13 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
 	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
 	apisv1alpha1 "github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/clients"
 	"github.com/marquesgui/provider-gocd/internal/controller/helper"
+	"github.com/marquesgui/provider-gocd/internal/drift"
+	"github.com/marquesgui/provider-gocd/internal/dryrun"
 	"github.com/marquesgui/provider-gocd/internal/features"
+	"github.com/marquesgui/provider-gocd/internal/ignore"
+	"github.com/marquesgui/provider-gocd/internal/tracing"
 	"github.com/marquesgui/provider-gocd/pkg/gocd"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -50,22 +55,9 @@ const (
 	errNewClient              = "cannot create new Service"
 )
 
-var newService = func(creds []byte) (any, error) {
-	cfg, err := apisv1alpha1.ParseGocdProviderConfig(creds)
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot parse gocd provider config")
-	}
-	c, err := gocd.New(gocd.Config{
-		BaseURL:  cfg.BaseURL,
-		Username: cfg.Username,
-		Password: cfg.Password,
-		Token:    cfg.Token,
-		Insecure: cfg.Insecure,
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot create new GoCD client")
-	}
-	return c.ElasticAgentProfile(), nil
+// newService returns the GoCD service this controller manages.
+var newService = func(gc gocd.Client) any {
+	return gc.ElasticAgentProfile()
 }
 
 // Setup adds a controller that reconciles ElasticAgentProfile managed resources.
@@ -77,15 +69,18 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	opts := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(tracing.NewConnector(v1alpha1.ElasticAgentProfileKind, dryrun.NewConnector(&connector{
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients:      clients.DefaultCache,
 			newServiceFn: newService,
-		}),
+			drift:        drift.NewReporter(recorder),
+		}, o.Features.Enabled(features.EnableDryRun), recorder))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...),
 		managed.WithManagementPolicies(),
 	}
@@ -122,7 +117,9 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds []byte) (interface{}, error)
+	clients      *clients.Cache
+	newServiceFn func(gc gocd.Client) any
+	drift        *drift.Reporter
 }
 
 // Connect typically produces an ExternalClient by:
@@ -145,28 +142,37 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err := clients.CheckHealth(pc); err != nil {
+		return nil, err
+	}
+
+	creds, err := clients.ExtractCredentials(ctx, c.kube, pc)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(data)
+	gc, err := c.clients.Get(pc, creds)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
+	svc := c.newServiceFn(gc)
+
 	s, ok := svc.(gocd.ElasticAgentProfileService)
 	if !ok {
 		return nil, errors.New("returned service does not implement gocd.ElasticAgentProfileService")
 	}
 
-	return &external{service: s, kube: c.kube}, nil
+	return &external{service: s, kube: c.kube, drift: c.drift}, nil
 }
 
 type external struct {
 	service gocd.ElasticAgentProfileService
 	kube    client.Client
+	drift   *drift.Reporter
+	// The profile as observed during this reconcile, which ignored fields are
+	// filled from when it is updated.
+	observed *gocd.ElasticAgentProfile
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -187,13 +193,30 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if got == nil {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+	c.observed = &got.ElasticAgentProfile
 
 	if err := updateStatus(ea, got); err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, "could not update the satus")
 	}
 
 	helper.KeepETag(ea, etag)
-	upToDate := isUpToDate(ctx, c.kube, ea, got)
+	desired := gocd.ElasticAgentProfile{
+		ID:               id,
+		ClusterProfileID: ea.Spec.ForProvider.ClusterProfileID,
+		Properties:       mapProperties(ea.Spec.ForProvider.Properties),
+	}
+	if err := ignore.Apply(&desired, &got.ElasticAgentProfile, ea.Spec.IgnoreChanges); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot ignore changes")
+	}
+	upToDate := isUpToDate(ea, desired, got)
+
+	var diffs []drift.Difference
+	if !upToDate {
+		if diffs, err = drift.Diff(desired, got.ElasticAgentProfile); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot compare the elastic agent profile")
+		}
+	}
+	c.drift.Report(ea, &ea.Status.Drift, diffs)
 
 	if upToDate {
 		ea.SetConditions(xpv1.Available())
@@ -252,6 +275,9 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		ClusterProfileID: cr.Spec.ForProvider.ClusterProfileID,
 		Properties:       mapProperties(cr.Spec.ForProvider.Properties),
 	}
+	if err := ignore.Apply(&rb, c.observed, cr.Spec.IgnoreChanges); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot ignore changes")
+	}
 
 	etag := helper.GetETag(cr)
 	got, newEtag, err := c.service.Update(ctx, rb, etag)
@@ -297,8 +323,8 @@ func updateStatus(ea *v1alpha1.ElasticAgentProfile, got *gocd.ElasticAgentProfil
 	return nil
 }
 
-func isUpToDate(ctx context.Context, kube client.Client, ea *v1alpha1.ElasticAgentProfile, got *gocd.ElasticAgentProfileResponse) bool {
-	propertiesAreEqual := func(eaProperties []v1alpha1.ConfigProperty, gotProperties []gocd.ConfigProperty) bool {
+func isUpToDate(ea *v1alpha1.ElasticAgentProfile, desired gocd.ElasticAgentProfile, got *gocd.ElasticAgentProfileResponse) bool {
+	propertiesAreEqual := func(eaProperties []gocd.ConfigProperty, gotProperties []gocd.ConfigProperty) bool {
 		if len(eaProperties) != len(gotProperties) {
 			return false
 		}
@@ -330,16 +356,17 @@ func isUpToDate(ctx context.Context, kube client.Client, ea *v1alpha1.ElasticAge
 	}
 
 	return ea.Spec.ForProvider.ID == got.ID &&
-		ea.Spec.ForProvider.ClusterProfileID == got.ClusterProfileID &&
-		propertiesAreEqual(ea.Spec.ForProvider.Properties, got.Properties)
+		desired.ClusterProfileID == got.ClusterProfileID &&
+		propertiesAreEqual(desired.Properties, got.Properties)
 }
 
 func mapProperties(p []v1alpha1.ConfigProperty) []gocd.ConfigProperty {
 	prts := make([]gocd.ConfigProperty, 0, 0)
 	for _, v := range p {
 		prts = append(prts, gocd.ConfigProperty{
-			Key:   v.Key,
-			Value: v.Value,
+			Key:            v.Key,
+			Value:          v.Value,
+			EncryptedValue: v.EncryptedValue,
 		})
 	}
 	return prts
@@ -31,6 +31,7 @@ import (
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	for _, setup := range []func(ctrl.Manager, controller.Options) error{
 		config.Setup,
+		config.SetupHealth,
 		authorizationconfiguration.Setup,
 		role.Setup,
 		pipelineconfig.Setup,
@@ -18,64 +18,53 @@ package pipelineconfig
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 
 	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-// ToSha256 returns the sha256 hash of a given string
-func ToSha256(s string) string {
-	h := sha256.New()
-	h.Write([]byte(s))
-	return hex.EncodeToString(h.Sum(nil))
+// resolve maps spec to the pipeline sent to GoCD. Referenced Secrets and
+// ConfigMaps are read once; the values of environment variables are resolved
+// in the returned pipeline, so hashes and encrypted values are derived from it
+// rather than read again.
+func resolve(ctx context.Context, kube client.Reader, spec v1alpha1.PipelineConfigForProvider) (*gocd.PipelineConfig, error) {
+	desired, err := mapAPIToDtoPipelineConfig(ctx, kube, spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot map api to dto")
+	}
+	return desired, nil
 }
 
-func calculateHashes(ctx context.Context, kube client.Client, pc v1alpha1.PipelineConfigForProvider) (map[string]string, error) {
-	hashes := make(map[string]string)
-
-	// Helper function to process environment variables
-	processEnvVars := func(envVars []v1alpha1.EnvironmentVariable, prefix string) error {
-		for _, v := range envVars {
-			var value string
-			if v.Value != "" {
-				value = v.Value
-			} else if v.ValueFrom != nil {
-				var err error
-				value, _, err = GetValueFrom(ctx, kube, v.ValueFrom)
-				if err != nil {
-					return errors.Wrapf(err, "failed to get value for environment variable %s", v.Name)
-				}
+// forEachVariable calls fn with every environment variable of a pipeline and a
+// key identifying it by the level it is set at, e.g. job.build.compile.TOKEN.
+func forEachVariable(pc *gocd.PipelineConfig, fn func(key string, v *gocd.EnvironmentVariable) error) error {
+	each := func(envVars []gocd.EnvironmentVariable, prefix string) error {
+		for i := range envVars {
+			if err := fn(fmt.Sprintf("%s.%s", prefix, envVars[i].Name), &envVars[i]); err != nil {
+				return err
 			}
-			key := fmt.Sprintf("%s.%s", prefix, v.Name)
-			hashes[key] = ToSha256(value)
 		}
 		return nil
 	}
 
 	// Pipeline-level environment variables
-	if err := processEnvVars(pc.EnvironmentVariables, "pipeline"); err != nil {
-		return nil, err
+	if err := each(pc.EnvironmentVariables, "pipeline"); err != nil {
+		return err
 	}
-
-	// Stage-level environment variables
 	for _, s := range pc.Stages {
-		stagePrefix := fmt.Sprintf("stage.%s", s.Name)
-		if err := processEnvVars(s.EnvironmentVariables, stagePrefix); err != nil {
-			return nil, err
+		// Stage-level environment variables
+		if err := each(s.EnvironmentVariables, fmt.Sprintf("stage.%s", s.Name)); err != nil {
+			return err
 		}
-
 		// Job-level environment variables
 		for _, j := range s.Jobs {
-			jobPrefix := fmt.Sprintf("job.%s.%s", s.Name, j.Name)
-			if err := processEnvVars(j.EnvironmentVariables, jobPrefix); err != nil {
-				return nil, err
+			if err := each(j.EnvironmentVariables, fmt.Sprintf("job.%s.%s", s.Name, j.Name)); err != nil {
+				return err
 			}
 		}
 	}
-
-	return hashes, nil
+	return nil
 }
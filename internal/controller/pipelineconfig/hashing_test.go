@@ -28,15 +28,32 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/marquesgui/provider-gocd/internal/keyring"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
 )
 
-func TestCalculateHashes(t *testing.T) {
+// testKeys hashes values in tests.
+var testKeys, _ = keyring.New("1", map[string][]byte{"1": []byte("test-key")})
+
+// values returns the values of the environment variables of a pipeline, keyed
+// as by forEachVariable.
+func values(pc *gocd.PipelineConfig) map[string]string {
+	out := make(map[string]string)
+	_ = forEachVariable(pc, func(key string, v *gocd.EnvironmentVariable) error {
+		out[key] = v.Value
+		return nil
+	})
+	return out
+}
+
+func TestResolveVariables(t *testing.T) {
 	type args struct {
 		kube client.Client
 		pc   v1alpha1.PipelineConfigForProvider
 	}
 	type want struct {
-		hashes map[string]string
+		values map[string]string
 		err    error
 	}
 
@@ -46,7 +63,7 @@ func TestCalculateHashes(t *testing.T) {
 		want   want
 	}{
 		"SimplePipeline": {
-			reason: "Should correctly calculate hashes for a simple pipeline with only pipeline-level environment variables.",
+			reason: "Should resolve the variables of a simple pipeline with only pipeline-level environment variables.",
 			args: args{
 				kube: fake.NewClientBuilder().Build(),
 				pc: v1alpha1.PipelineConfigForProvider{
@@ -57,14 +74,14 @@ func TestCalculateHashes(t *testing.T) {
 				},
 			},
 			want: want{
-				hashes: map[string]string{
-					"pipeline.VAR1": ToSha256("VALUE1"),
-					"pipeline.VAR2": ToSha256("VALUE2"),
+				values: map[string]string{
+					"pipeline.VAR1": "VALUE1",
+					"pipeline.VAR2": "VALUE2",
 				},
 			},
 		},
 		"ComplexPipeline": {
-			reason: "Should correctly calculate hashes for a complex pipeline with environment variables at all levels.",
+			reason: "Should resolve the variables of a complex pipeline with environment variables at all levels.",
 			args: args{
 				kube: fake.NewClientBuilder().WithRuntimeObjects(
 					&corev1.Secret{
@@ -103,10 +120,41 @@ func TestCalculateHashes(t *testing.T) {
 				},
 			},
 			want: want{
-				hashes: map[string]string{
-					"pipeline.PIPELINE_VAR":   ToSha256("pipelineValue"),
-					"stage.stage1.STAGE_VAR":  ToSha256("stageValue"),
-					"job.stage1.job1.JOB_VAR": ToSha256("secretValue"),
+				values: map[string]string{
+					"pipeline.PIPELINE_VAR":   "pipelineValue",
+					"stage.stage1.STAGE_VAR":  "stageValue",
+					"job.stage1.job1.JOB_VAR": "secretValue",
+				},
+			},
+		},
+		"EnvFrom": {
+			reason: "Should resolve every variable imported from Secrets and ConfigMaps.",
+			args: args{
+				kube: fake.NewClientBuilder().WithRuntimeObjects(
+					&corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "default"},
+						Data:       map[string][]byte{"TOKEN": []byte("secretValue")},
+					},
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: "config1", Namespace: "default"},
+						Data:       map[string]string{"REGISTRY": "registry.example.com"},
+					},
+				).Build(),
+				pc: v1alpha1.PipelineConfigForProvider{
+					EnvFrom: []v1alpha1.EnvFromSource{{ConfigMapRef: &v1alpha1.ConfigMapReference{Name: "config1", Namespace: "default"}}},
+					Stages: []v1alpha1.Stage{{
+						Name: "stage1",
+						Jobs: []v1alpha1.Job{{
+							Name:    "job1",
+							EnvFrom: []v1alpha1.EnvFromSource{{Prefix: "DEPLOY_", SecretRef: &xpv1.SecretReference{Name: "secret1", Namespace: "default"}}},
+						}},
+					}},
+				},
+			},
+			want: want{
+				values: map[string]string{
+					"pipeline.REGISTRY":            "registry.example.com",
+					"job.stage1.job1.DEPLOY_TOKEN": "secretValue",
 				},
 			},
 		},
@@ -114,13 +162,76 @@ func TestCalculateHashes(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			hashes, err := calculateHashes(context.Background(), tc.args.kube, tc.args.pc)
+			var got map[string]string
+			desired, err := resolve(context.Background(), tc.args.kube, tc.args.pc)
+			if err == nil {
+				got = values(desired)
+			}
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
-				t.Errorf("\n%s\ncalculateHashes(...): -want error, +got error:\n%s", tc.reason, diff)
+				t.Errorf("\n%s\nresolve(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
-			if diff := cmp.Diff(tc.want.hashes, hashes); diff != "" {
-				t.Errorf("\n%s\ncalculateHashes(...): -want, +got:\n%s", tc.reason, diff)
+			if diff := cmp.Diff(tc.want.values, got); diff != "" {
+				t.Errorf("\n%s\nresolve(...): -want, +got:\n%s", tc.reason, diff)
 			}
 		})
 	}
 }
+
+// countingReader counts the objects read through it.
+type countingReader struct {
+	client.Reader
+	gets int
+}
+
+func (r *countingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	r.gets++
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
+
+func TestResolveReadsEachObjectOnce(t *testing.T) {
+	ref := func(key string) *v1alpha1.ValueSource {
+		return &v1alpha1.ValueSource{SecretKeyRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: "secret1", Namespace: "default"},
+			Key:             key,
+		}}
+	}
+	pc := v1alpha1.PipelineConfigForProvider{
+		EnvironmentVariables: []v1alpha1.EnvironmentVariable{{Name: "USER", ValueFrom: ref("user")}},
+		Stages: []v1alpha1.Stage{{
+			Name:                 "stage1",
+			EnvironmentVariables: []v1alpha1.EnvironmentVariable{{Name: "PASSWORD", ValueFrom: ref("password")}},
+			Jobs: []v1alpha1.Job{{
+				Name:                 "job1",
+				EnvironmentVariables: []v1alpha1.EnvironmentVariable{{Name: "MISSING", ValueFrom: ref("missing")}},
+			}},
+		}},
+	}
+	counter := &countingReader{Reader: fake.NewClientBuilder().WithRuntimeObjects(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "secret1", Namespace: "default"},
+		Data:       map[string][]byte{"user": []byte("bot"), "password": []byte("hunter2")},
+	}).Build()}
+	kube := newCachingReader(counter)
+
+	if _, err := resolve(context.Background(), kube, pc); err == nil {
+		t.Fatalf("resolve(...): want error for missing key")
+	}
+	pc.Stages[0].Jobs[0].EnvironmentVariables = nil
+	desired, err := resolve(context.Background(), kube, pc)
+	if err != nil {
+		t.Fatalf("resolve(...): %v", err)
+	}
+	if counter.gets != 1 {
+		t.Errorf("resolve(...): want the secret read once, got %d reads", counter.gets)
+	}
+
+	want := map[string]string{
+		"pipeline.USER":         "bot",
+		"stage.stage1.PASSWORD": "hunter2",
+	}
+	if diff := cmp.Diff(want, values(desired)); diff != "" {
+		t.Errorf("resolve(...): -want values, +got:\n%s", diff)
+	}
+	if got := desired.Stages[0].EnvironmentVariables[0]; got.Value != "hunter2" || !got.Secure {
+		t.Errorf("resolve(...): want resolved secure variable, got %+v", got)
+	}
+}
@@ -6,7 +6,9 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/tracing"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -17,7 +19,7 @@ const (
 )
 
 // GetSecretValue retrieves the value of a secret key from a Kubernetes secret.
-func GetSecretValue(ctx context.Context, kube client.Client, selector *xpv1.SecretKeySelector) (string, error) {
+func GetSecretValue(ctx context.Context, kube client.Reader, selector *xpv1.SecretKeySelector) (string, error) {
 	nn := types.NamespacedName{
 		Name:      selector.Name,
 		Namespace: selector.Namespace,
@@ -36,8 +38,16 @@ func GetSecretValue(ctx context.Context, kube client.Client, selector *xpv1.Secr
 }
 
 // GetValueFrom retrieves the value from a given environment variable source.
-func GetValueFrom(ctx context.Context, kube client.Client, from *v1alpha1.ValueSource) (string, bool, error) {
+func GetValueFrom(ctx context.Context, kube client.Reader, from *v1alpha1.ValueSource) (value string, secure bool, err error) {
+	ctx, span := tracing.Start(ctx, "GetValueFrom")
+	defer func() { tracing.End(span, err) }()
+
 	if from.ConfigMapKeyRef != nil {
+		span.SetAttributes(
+			attribute.String("k8s.configmap.name", from.ConfigMapKeyRef.Name),
+			attribute.String("k8s.namespace.name", from.ConfigMapKeyRef.Namespace),
+			attribute.String("k8s.configmap.key", from.ConfigMapKeyRef.Key),
+		)
 		nn := types.NamespacedName{
 			Name:      from.ConfigMapKeyRef.Name,
 			Namespace: from.ConfigMapKeyRef.Namespace,
@@ -54,8 +64,106 @@ func GetValueFrom(ctx context.Context, kube client.Client, from *v1alpha1.ValueS
 		return val, false, nil
 	}
 	if from.SecretKeyRef != nil {
+		span.SetAttributes(
+			attribute.String("k8s.secret.name", from.SecretKeyRef.Name),
+			attribute.String("k8s.namespace.name", from.SecretKeyRef.Namespace),
+			attribute.String("k8s.secret.key", from.SecretKeyRef.Key),
+		)
 		val, err := GetSecretValue(ctx, kube, from.SecretKeyRef)
 		return val, true, err
 	}
 	return "", false, nil
 }
+
+// GetEnvFrom retrieves every key of the Secret or ConfigMap of an envFrom
+// source, and whether its values are secure.
+func GetEnvFrom(ctx context.Context, kube client.Reader, from v1alpha1.EnvFromSource) (values map[string]string, secure bool, err error) {
+	ctx, span := tracing.Start(ctx, "GetEnvFrom")
+	defer func() { tracing.End(span, err) }()
+
+	if from.ConfigMapRef != nil {
+		span.SetAttributes(
+			attribute.String("k8s.configmap.name", from.ConfigMapRef.Name),
+			attribute.String("k8s.namespace.name", from.ConfigMapRef.Namespace),
+		)
+		var cm corev1.ConfigMap
+		if err := kube.Get(ctx, types.NamespacedName{Name: from.ConfigMapRef.Name, Namespace: from.ConfigMapRef.Namespace}, &cm); err != nil {
+			return nil, false, errors.Wrap(err, "cannot get configmap")
+		}
+		return cm.Data, from.Secure, nil
+	}
+	if from.SecretRef != nil {
+		span.SetAttributes(
+			attribute.String("k8s.secret.name", from.SecretRef.Name),
+			attribute.String("k8s.namespace.name", from.SecretRef.Namespace),
+		)
+		var secret corev1.Secret
+		if err := kube.Get(ctx, types.NamespacedName{Name: from.SecretRef.Name, Namespace: from.SecretRef.Namespace}, &secret); err != nil {
+			return nil, false, errors.Wrap(err, errGetSecret)
+		}
+		values := make(map[string]string, len(secret.Data))
+		for k, v := range secret.Data {
+			values[k] = string(v)
+		}
+		return values, true, nil
+	}
+	return nil, false, nil
+}
+
+// A cachingReader reads each Secret and ConfigMap at most once. An external
+// client is connected for every reconcile, so wrapping its reader caches the
+// values of a pipeline for the duration of a reconcile.
+type cachingReader struct {
+	client.Reader
+	objects map[objectKey]cachedObject
+}
+
+type objectKey struct {
+	kind string
+	types.NamespacedName
+}
+
+type cachedObject struct {
+	obj client.Object
+	err error
+}
+
+func newCachingReader(r client.Reader) *cachingReader {
+	return &cachingReader{Reader: r, objects: make(map[objectKey]cachedObject)}
+}
+
+// Get reads a Secret or ConfigMap from the cache, or from the underlying
+// reader on a miss. Failed reads are cached too. Other kinds are not cached.
+func (r *cachingReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	var kind string
+	switch obj.(type) {
+	case *corev1.Secret:
+		kind = "Secret"
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	default:
+		return r.Reader.Get(ctx, key, obj, opts...)
+	}
+
+	k := objectKey{kind: kind, NamespacedName: key}
+	c, ok := r.objects[k]
+	if !ok {
+		err := r.Reader.Get(ctx, key, obj, opts...)
+		c = cachedObject{err: err}
+		if err == nil {
+			c.obj = obj.DeepCopyObject().(client.Object)
+		}
+		r.objects[k] = c
+		return err
+	}
+	if c.err != nil {
+		return c.err
+	}
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		c.obj.(*corev1.Secret).DeepCopyInto(o)
+	case *corev1.ConfigMap:
+		c.obj.(*corev1.ConfigMap).DeepCopyInto(o)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelineconfig
+
+import (
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/lateinit"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+// lateInitialize fills the unset fields of spec with the defaults GoCD applied
+// to the observed pipeline, e.g. the label template or job timeouts. Materials
+// and tasks are matched by position, stages and jobs by name. Optional fields
+// GoCD does not default, such as the template, timer or parameters, are never
+// late initialized, so that removing them from spec removes them from GoCD.
+// It reports whether spec was changed.
+func lateInitialize(spec *v1alpha1.PipelineConfigForProvider, got *gocd.PipelineConfig) bool {
+	observed := MapDTOToAPIPipelineConfig(got, nil)
+
+	li := lateinit.Any(
+		lateinit.Value(&spec.LabelTemplate, observed.LabelTemplate),
+		lateinit.Value(&spec.LockBehavior, observed.LockBehavior),
+		lateinit.Value(&spec.Origin.Type, observed.Origin.Type),
+	)
+
+	for i := range spec.Materials {
+		if i < len(observed.Materials) {
+			li = lateInitializeMaterial(&spec.Materials[i], observed.Materials[i]) || li
+		}
+	}
+
+	stages := make(map[string]v1alpha1.Stage, len(observed.Stages))
+	for _, s := range observed.Stages {
+		stages[s.Name] = s
+	}
+	for i := range spec.Stages {
+		if s, ok := stages[spec.Stages[i].Name]; ok {
+			li = lateInitializeStage(&spec.Stages[i], s) || li
+		}
+	}
+	return li
+}
+
+func lateInitializeMaterial(m *v1alpha1.Material, observed v1alpha1.Material) bool {
+	if m.Type != observed.Type {
+		return false
+	}
+	switch m.Type {
+	case v1alpha1.MaterialTypeGit:
+		if m.GitAttributes != nil && observed.GitAttributes != nil {
+			return lateinit.Any(
+				lateinit.Value(&m.GitAttributes.Name, observed.GitAttributes.Name),
+				lateinit.Value(&m.GitAttributes.Branch, observed.GitAttributes.Branch),
+			)
+		}
+	case v1alpha1.MaterialTypeSvn:
+		if m.SvnAttributes != nil && observed.SvnAttributes != nil {
+			return lateinit.Value(&m.SvnAttributes.Name, observed.SvnAttributes.Name)
+		}
+	case v1alpha1.MaterialTypeHg:
+		if m.HgAttributes != nil && observed.HgAttributes != nil {
+			return lateinit.Any(
+				lateinit.Value(&m.HgAttributes.Name, observed.HgAttributes.Name),
+				lateinit.Value(&m.HgAttributes.Branch, observed.HgAttributes.Branch),
+			)
+		}
+	case v1alpha1.MaterialTypeP4:
+		if m.P4Attributes != nil && observed.P4Attributes != nil {
+			return lateinit.Value(&m.P4Attributes.Name, observed.P4Attributes.Name)
+		}
+	case v1alpha1.MaterialTypeTfs:
+		if m.TfsAttributes != nil && observed.TfsAttributes != nil {
+			return lateinit.Value(&m.TfsAttributes.Name, observed.TfsAttributes.Name)
+		}
+	case v1alpha1.MaterialTypeDependency:
+		if m.DependencyAttributes != nil && observed.DependencyAttributes != nil {
+			return lateinit.Value(&m.DependencyAttributes.Name, observed.DependencyAttributes.Name)
+		}
+	case v1alpha1.MaterialTypePackage, v1alpha1.MaterialTypePlugin:
+	}
+	return false
+}
+
+func lateInitializeStage(s *v1alpha1.Stage, observed v1alpha1.Stage) bool {
+	li := lateinit.Value(&s.Approval.Type, observed.Approval.Type)
+
+	jobs := make(map[string]v1alpha1.Job, len(observed.Jobs))
+	for _, j := range observed.Jobs {
+		jobs[j.Name] = j
+	}
+	for i := range s.Jobs {
+		j, ok := jobs[s.Jobs[i].Name]
+		if !ok {
+			continue
+		}
+		li = lateinit.Any(
+			lateinit.Value(&s.Jobs[i].Timeout, j.Timeout),
+			lateinit.Value(&s.Jobs[i].RunInstanceCount, j.RunInstanceCount),
+		) || li
+		for k := range s.Jobs[i].Tasks {
+			if k < len(j.Tasks) {
+				li = lateInitializeTask(&s.Jobs[i].Tasks[k], j.Tasks[k]) || li
+			}
+		}
+	}
+	return li
+}
+
+// lateInitializeTask fills the run_if conditions GoCD defaults to passed.
+func lateInitializeTask(t *v1alpha1.TaskWithCancel, observed v1alpha1.TaskWithCancel) bool {
+	if t.Type != observed.Type {
+		return false
+	}
+	switch t.Type {
+	case v1alpha1.TaskTypeExec:
+		if t.ExecAttributes != nil && observed.ExecAttributes != nil {
+			return lateinit.Slice(&t.ExecAttributes.RunIf, observed.ExecAttributes.RunIf)
+		}
+	case v1alpha1.TaskTypeAnt:
+		if t.AntAttributes != nil && observed.AntAttributes != nil {
+			return lateinit.Slice(&t.AntAttributes.RunIf, observed.AntAttributes.RunIf)
+		}
+	case v1alpha1.TaskTypeNant:
+		if t.NantAttributes != nil && observed.NantAttributes != nil {
+			return lateinit.Slice(&t.NantAttributes.RunIf, observed.NantAttributes.RunIf)
+		}
+	case v1alpha1.TaskTypeRake:
+		if t.RakeAttributes != nil && observed.RakeAttributes != nil {
+			return lateinit.Slice(&t.RakeAttributes.RunIf, observed.RakeAttributes.RunIf)
+		}
+	case v1alpha1.TaskTypeFetch:
+		if t.FetchAttributes != nil && observed.FetchAttributes != nil {
+			return lateinit.Slice(&t.FetchAttributes.RunIf, observed.FetchAttributes.RunIf)
+		}
+	case v1alpha1.TaskTypePluggable:
+		if t.PluggableAttributes != nil && observed.PluggableAttributes != nil {
+			return lateinit.Slice(&t.PluggableAttributes.RunIf, observed.PluggableAttributes.RunIf)
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelineconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
+)
+
+// observedPipeline is a pipeline as GoCD returns it after creating it from a
+// spec that only set the fields the user cares about.
+const observedPipeline = `{
+  "name": "up42",
+  "group": "first",
+  "label_template": "${COUNT}",
+  "lock_behavior": "none",
+  "template": null,
+  "origin": {"type": "gocd"},
+  "environment_variables": [{"name": "TOKEN", "encrypted_value": "AES:abc", "secure": true}],
+  "materials": [{"type": "git", "attributes": {"name": "up42-repo", "url": "https://example.com/up42.git", "branch": "master", "auto_update": true}}],
+  "stages": [{
+    "name": "build",
+    "fetch_materials": true,
+    "approval": {"type": "success", "authorization": {"users": [], "roles": []}},
+    "jobs": [{
+      "name": "compile",
+      "timeout": "never",
+      "run_instance_count": null,
+      "tasks": [{"type": "exec", "attributes": {"command": "make", "run_if": ["passed"]}}]
+    }]
+  }]
+}`
+
+func TestLateInitialize(t *testing.T) {
+	got := &gocd.PipelineConfig{}
+	if err := json.Unmarshal([]byte(observedPipeline), got); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := v1alpha1.PipelineConfigForProvider{
+		Group: "first",
+		Materials: []v1alpha1.Material{{
+			Type:          v1alpha1.MaterialTypeGit,
+			GitAttributes: &v1alpha1.MaterialAttributesGit{URL: "https://example.com/up42.git", AutoUpdate: true},
+		}},
+		Stages: []v1alpha1.Stage{{
+			Name:           "build",
+			FetchMaterials: true,
+			Jobs: []v1alpha1.Job{{
+				Name: "compile",
+				Tasks: []v1alpha1.TaskWithCancel{{
+					Type:           v1alpha1.TaskTypeExec,
+					ExecAttributes: &v1alpha1.TaskExecAttributesWithCancel{TaskExecAttributes: v1alpha1.TaskExecAttributes{Command: "make"}},
+				}},
+			}},
+		}},
+	}
+
+	want := *spec.DeepCopy()
+	want.LabelTemplate = "${COUNT}"
+	want.LockBehavior = v1alpha1.LockBehaviorNone
+	want.Origin.Type = v1alpha1.OriginTypeGoCD
+	want.Materials[0].GitAttributes.Name = "up42-repo"
+	want.Materials[0].GitAttributes.Branch = "master"
+	want.Stages[0].Approval.Type = v1alpha1.StageApprovalTypeSuccess
+	want.Stages[0].Jobs[0].Timeout = intstr.FromString("never")
+	want.Stages[0].Jobs[0].Tasks[0].ExecAttributes.RunIf = []v1alpha1.TaskAttributesRunIfTypes{v1alpha1.TaskExecAttributesRunIfTypesPassed}
+
+	if !lateInitialize(&spec, got) {
+		t.Errorf("lateInitialize(...): want spec changed")
+	}
+	if diff := cmp.Diff(want, spec); diff != "" {
+		t.Errorf("lateInitialize(...): -want, +got:\n%s", diff)
+	}
+	if spec.EnvironmentVariables != nil {
+		t.Errorf("lateInitialize(...): environment variables must not be late initialized, got %+v", spec.EnvironmentVariables)
+	}
+
+	if lateInitialize(&spec, got) {
+		t.Errorf("lateInitialize(...): want a late initialized spec to converge")
+	}
+
+	spec.LabelTemplate = "${COUNT}-${git[:8]}"
+	if lateInitialize(&spec, got) || spec.LabelTemplate != "${COUNT}-${git[:8]}" {
+		t.Errorf("lateInitialize(...): must not override fields set in spec, got %q", spec.LabelTemplate)
+	}
+}
+
+func TestLateInitializeKeepsClearedFields(t *testing.T) {
+	got := &gocd.PipelineConfig{}
+	if err := json.Unmarshal([]byte(observedPipeline), got); err != nil {
+		t.Fatal(err)
+	}
+	// The spec previously set these fields and they were removed from it.
+	got.Template = ptr.ToPtr("build-template")
+	got.Timer = &gocd.PipelineConfigTimer{Spec: "0 0 22 ? * MON-FRI"}
+	got.TrackingTool = &gocd.PipelineConfigTrackingTool{Type: "generic"}
+	got.Parameters = []gocd.PipelineConfigParameter{{Name: "ENV", Value: "prod"}}
+	got.Stages[0].Jobs[0].ElasticProfileID = "docker"
+
+	spec := v1alpha1.PipelineConfigForProvider{
+		Stages: []v1alpha1.Stage{{Name: "build", Jobs: []v1alpha1.Job{{Name: "compile"}}}},
+	}
+	lateInitialize(&spec, got)
+
+	if spec.Group != "" || spec.Template != "" || spec.Timer != (v1alpha1.Timer{}) || spec.TrackingTool != (v1alpha1.TrackingTool{}) || spec.Parameters != nil {
+		t.Errorf("lateInitialize(...): want removed fields to stay removed, got %+v", spec)
+	}
+	if spec.Stages[0].Jobs[0].ElasticProfileID != "" {
+		t.Errorf("lateInitialize(...): want removed elastic profile to stay removed, got %q", spec.Stages[0].Jobs[0].ElasticProfileID)
+	}
+}
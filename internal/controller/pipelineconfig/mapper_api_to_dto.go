@@ -2,9 +2,12 @@ package pipelineconfig
 
 import (
 	"context"
+	"maps"
+	"slices"
 
 	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
 	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -31,8 +34,13 @@ func intstrOrNil(i intstr.IntOrString) *intstr.IntOrString {
 	return &i
 }
 
-func mapAPIToDtoPipelineConfig(ctx context.Context, kubeClient client.Client, cr v1alpha1.PipelineConfigForProvider) (*gocd.PipelineConfig, error) {
-	envVars, err := mapAPIEnvironmentVariablesToDTO(ctx, kubeClient, cr.EnvironmentVariables)
+func mapAPIToDtoPipelineConfig(ctx context.Context, kubeClient client.Reader, cr v1alpha1.PipelineConfigForProvider) (*gocd.PipelineConfig, error) {
+	envVars, err := mapAPIEnvironmentVariablesToDTO(ctx, kubeClient, cr.EnvFrom, cr.EnvironmentVariables)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while mapping api to dto")
+	}
+
+	params, err := mapAPIParametersToDTO(ctx, kubeClient, cr.Parameters)
 	if err != nil {
 		return nil, errors.Wrap(err, "error while mapping api to dto")
 	}
@@ -49,7 +57,7 @@ func mapAPIToDtoPipelineConfig(ctx context.Context, kubeClient client.Client, cr
 		Name:                 stringOrNil(cr.Name),
 		Template:             stringOrNil(cr.Template),
 		Origin:               mapAPIOriginToDTO(cr.Origin),
-		Parameters:           mapAPIParametersToDTO(cr.Parameters),
+		Parameters:           params,
 		EnvironmentVariables: envVars,
 		Materials:            mapAPIMaterialsToDTO(cr.Materials),
 		Stages:               stages,
@@ -90,10 +98,10 @@ func mapAPIOriginToDTO(o v1alpha1.Origin) *gocd.PipelineConfigOrigin {
 	}
 }
 
-func mapAPIStagesToDto(ctx context.Context, kubeClient client.Client, stages []v1alpha1.Stage) ([]gocd.PipelineConfigStage, error) {
+func mapAPIStagesToDto(ctx context.Context, kubeClient client.Reader, stages []v1alpha1.Stage) ([]gocd.PipelineConfigStage, error) {
 	out := make([]gocd.PipelineConfigStage, 0, len(stages))
 	for _, v := range stages {
-		envVars, err := mapAPIEnvironmentVariablesToDTO(ctx, kubeClient, v.EnvironmentVariables)
+		envVars, err := mapAPIEnvironmentVariablesToDTO(ctx, kubeClient, v.EnvFrom, v.EnvironmentVariables)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not map the env vars for the stage")
 		}
@@ -116,10 +124,10 @@ func mapAPIStagesToDto(ctx context.Context, kubeClient client.Client, stages []v
 	return out, nil
 }
 
-func mapAPIStageJobsToDto(ctx context.Context, kubeClient client.Client, jobs []v1alpha1.Job) ([]gocd.PipelineConfigStageJobs, error) {
+func mapAPIStageJobsToDto(ctx context.Context, kubeClient client.Reader, jobs []v1alpha1.Job) ([]gocd.PipelineConfigStageJobs, error) {
 	out := make([]gocd.PipelineConfigStageJobs, 0, len(jobs))
 	for _, v := range jobs {
-		envVars, err := mapAPIEnvironmentVariablesToDTO(ctx, kubeClient, v.EnvironmentVariables)
+		envVars, err := mapAPIEnvironmentVariablesToDTO(ctx, kubeClient, v.EnvFrom, v.EnvironmentVariables)
 		if err != nil {
 			return nil, errors.Wrap(err, "could no map env var for the job")
 		}
@@ -343,8 +351,9 @@ func mapJobTaskConfiguration(configuration []v1alpha1.KeyValue) []gocd.ConfigPro
 	out := make([]gocd.ConfigProperty, 0, len(configuration))
 	for _, v := range configuration {
 		out = append(out, gocd.ConfigProperty{
-			Key:   v.Key,
-			Value: v.Value,
+			Key:            v.Key,
+			Value:          v.Value,
+			EncryptedValue: v.EncryptedValue,
 		})
 	}
 	return out
@@ -545,7 +554,7 @@ func mapAPIMaterialGitAttributesToDTO(attributes *v1alpha1.MaterialAttributesGit
 }
 
 func mapAPIFilterToDTO(f v1alpha1.Filter) *gocd.PipelineConfigMaterialFilter {
-	if len(f.Ignore) == 0 {
+	if len(f.Ignore) == 0 && len(f.Includes) == 0 {
 		return nil
 	}
 
@@ -555,8 +564,29 @@ func mapAPIFilterToDTO(f v1alpha1.Filter) *gocd.PipelineConfigMaterialFilter {
 	}
 }
 
-func mapAPIEnvironmentVariablesToDTO(ctx context.Context, kubeClient client.Client, variables []v1alpha1.EnvironmentVariable) ([]gocd.EnvironmentVariable, error) { //nolint gocyclo
-	out := make([]gocd.EnvironmentVariable, 0, len(variables))
+// mapAPIEnvironmentVariablesToDTO resolves the variables imported by envFrom,
+// in order of their names, followed by the listed variables. A listed variable
+// replaces an imported one of the same name, as a later source replaces the
+// keys of an earlier one.
+func mapAPIEnvironmentVariablesToDTO(ctx context.Context, kubeClient client.Reader, envFrom []v1alpha1.EnvFromSource, variables []v1alpha1.EnvironmentVariable) ([]gocd.EnvironmentVariable, error) { //nolint gocyclo
+	imported := map[string]gocd.EnvironmentVariable{}
+	for i, from := range envFrom {
+		values, secure, err := GetEnvFrom(ctx, kubeClient, from)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get variables of envFrom[%d]", i)
+		}
+		for k, v := range values {
+			imported[from.Prefix+k] = gocd.EnvironmentVariable{Name: from.Prefix + k, Value: v, Secure: secure}
+		}
+	}
+	for _, v := range variables {
+		delete(imported, v.Name)
+	}
+
+	out := make([]gocd.EnvironmentVariable, 0, len(imported)+len(variables))
+	for _, name := range slices.Sorted(maps.Keys(imported)) {
+		out = append(out, imported[name])
+	}
 	for _, v := range variables {
 		var value string
 		var err error
@@ -580,13 +610,20 @@ func mapAPIEnvironmentVariablesToDTO(ctx context.Context, kubeClient client.Clie
 	return out, nil
 }
 
-func mapAPIParametersToDTO(parameters []v1alpha1.Parameter) []gocd.PipelineConfigParameter {
+func mapAPIParametersToDTO(ctx context.Context, kubeClient client.Reader, parameters []v1alpha1.Parameter) ([]gocd.PipelineConfigParameter, error) {
 	out := make([]gocd.PipelineConfigParameter, 0, len(parameters))
 	for _, p := range parameters {
+		value := ptr.Deref(p.Value)
+		if p.Value == nil && p.ValueFrom != nil {
+			var err error
+			if value, _, err = GetValueFrom(ctx, kubeClient, p.ValueFrom); err != nil {
+				return nil, errors.Wrapf(err, "failed to get value for parameter %s", p.Name)
+			}
+		}
 		out = append(out, gocd.PipelineConfigParameter{
 			Name:  p.Name,
-			Value: p.Value,
+			Value: value,
 		})
 	}
-	return out
+	return out, nil
 }
@@ -0,0 +1,450 @@
+package pipelineconfig
+
+import (
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// SecureValueFrom returns the source of a secure environment variable, whose
+// value GoCD only returns encrypted. scope holds the names of the stage and
+// job the variable is defined in, if any.
+type SecureValueFrom func(scope []string, name string) *v1alpha1.ValueSource
+
+// MapDTOToAPIPipelineConfig maps a pipeline config returned by GoCD to the
+// parameters of a PipelineConfig. Secure environment variables are read from
+// the source returned by secure, or left without a value if secure is nil.
+func MapDTOToAPIPipelineConfig(pc *gocd.PipelineConfig, secure SecureValueFrom) v1alpha1.PipelineConfigForProvider {
+	out := v1alpha1.PipelineConfigForProvider{
+		Group:                ptr.Deref(pc.Group),
+		LabelTemplate:        ptr.Deref(pc.LabelTemplate),
+		LockBehavior:         v1alpha1.LockBehavior(ptr.Deref(pc.LockBehavior)),
+		Name:                 ptr.Deref(pc.Name),
+		Template:             ptr.Deref(pc.Template),
+		Origin:               mapDTOOriginToAPI(pc.Origin),
+		Parameters:           mapDTOParametersToAPI(pc.Parameters),
+		EnvironmentVariables: mapDTOEnvironmentVariablesToAPI(pc.EnvironmentVariables, nil, secure),
+		Materials:            mapDTOMaterialsToAPI(pc.Materials),
+		Stages:               mapDTOStagesToAPI(pc.Stages, secure),
+	}
+	if pc.TrackingTool != nil {
+		out.TrackingTool = v1alpha1.TrackingTool{
+			Type: pc.TrackingTool.Type,
+			Attributes: v1alpha1.TrackingToolAttributes{
+				URLPattern: pc.TrackingTool.Attributes.URLPattern,
+				Regex:      pc.TrackingTool.Attributes.Regex,
+			},
+		}
+	}
+	if pc.Timer != nil {
+		out.Timer = v1alpha1.Timer{
+			Spec:          pc.Timer.Spec,
+			OnlyOnChanges: pc.Timer.OnlyOnChanges,
+		}
+	}
+	return out
+}
+
+func mapDTOOriginToAPI(o *gocd.PipelineConfigOrigin) v1alpha1.Origin {
+	if o == nil {
+		return v1alpha1.Origin{}
+	}
+	return v1alpha1.Origin{
+		Type: v1alpha1.OriginType(ptr.Deref(o.Type)),
+		ID:   ptr.Deref(o.ID),
+	}
+}
+
+func mapDTOParametersToAPI(parameters []gocd.PipelineConfigParameter) []v1alpha1.Parameter {
+	if len(parameters) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.Parameter, 0, len(parameters))
+	for _, p := range parameters {
+		out = append(out, v1alpha1.Parameter{
+			Name:  p.Name,
+			Value: ptr.ToPtr(p.Value),
+		})
+	}
+	return out
+}
+
+func mapDTOEnvironmentVariablesToAPI(variables []gocd.EnvironmentVariable, scope []string, secure SecureValueFrom) []v1alpha1.EnvironmentVariable {
+	if len(variables) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.EnvironmentVariable, 0, len(variables))
+	for _, v := range variables {
+		ev := v1alpha1.EnvironmentVariable{Name: v.Name}
+		switch {
+		case !v.Secure:
+			ev.Value = v.Value
+		case secure != nil:
+			ev.ValueFrom = secure(scope, v.Name)
+		}
+		out = append(out, ev)
+	}
+	return out
+}
+
+func mapDTOStagesToAPI(stages []gocd.PipelineConfigStage, secure SecureValueFrom) []v1alpha1.Stage {
+	if len(stages) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.Stage, 0, len(stages))
+	for _, s := range stages {
+		out = append(out, v1alpha1.Stage{
+			Name:                  s.Name,
+			FetchMaterials:        s.FetchMaterials,
+			CleanWorkingDir:       s.CleanWorkingDirectory,
+			NeverCleanupArtifacts: s.NeverCleanupArtifacts,
+			Approval:              mapDTOStageApprovalToAPI(s.Approval),
+			EnvironmentVariables:  mapDTOEnvironmentVariablesToAPI(s.EnvironmentVariables, []string{s.Name}, secure),
+			Jobs:                  mapDTOStageJobsToAPI(s.Jobs, s.Name, secure),
+		})
+	}
+	return out
+}
+
+func mapDTOStageApprovalToAPI(approval gocd.PipelineConfigApproval) v1alpha1.StageApproval {
+	return v1alpha1.StageApproval{
+		Type:               v1alpha1.StageApprovalType(approval.Type),
+		AllowOnlyOnSuccess: approval.AllowOnlyOnSuccess,
+		Authorization: v1alpha1.StageApprovalAuthorization{
+			Users: approval.Authorization.Users,
+			Roles: approval.Authorization.Roles,
+		},
+	}
+}
+
+func mapDTOStageJobsToAPI(jobs []gocd.PipelineConfigStageJobs, stage string, secure SecureValueFrom) []v1alpha1.Job {
+	if len(jobs) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.Job, 0, len(jobs))
+	for _, j := range jobs {
+		var runInstanceCount intstr.IntOrString
+		if j.RunInstanceCount != nil {
+			runInstanceCount = *j.RunInstanceCount
+		}
+		out = append(out, v1alpha1.Job{
+			Name:                 j.Name,
+			RunInstanceCount:     runInstanceCount,
+			Timeout:              j.Timeout,
+			EnvironmentVariables: mapDTOEnvironmentVariablesToAPI(j.EnvironmentVariables, []string{stage, j.Name}, secure),
+			Resources:            j.Resources,
+			Tasks:                mapDTOJobTasksToAPI(j.Tasks),
+			Tabs:                 mapDTOJobTabsToAPI(j.Tabs),
+			Artifacts:            mapDTOJobArtifactsToAPI(j.Artifacts),
+			ElasticProfileID:     j.ElasticProfileID,
+		})
+	}
+	return out
+}
+
+func mapDTOJobTabsToAPI(tabs []gocd.PipelineConfigStageJobsTab) []v1alpha1.JobTab {
+	if len(tabs) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.JobTab, 0, len(tabs))
+	for _, t := range tabs {
+		out = append(out, v1alpha1.JobTab{
+			Name: t.Name,
+			Path: t.Path,
+		})
+	}
+	return out
+}
+
+func mapDTOJobArtifactsToAPI(artifacts []gocd.PipelineConfigStageJobsArtifact) []v1alpha1.JobArtifact {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.JobArtifact, 0, len(artifacts))
+	for _, a := range artifacts {
+		out = append(out, v1alpha1.JobArtifact{
+			Type:          v1alpha1.JobArtifactType(a.Type),
+			Source:        ptr.Deref(a.Source),
+			Destination:   a.Destination,
+			ID:            ptr.Deref(a.ArtifactID),
+			StoreID:       a.StoreID,
+			Configuration: mapDTOConfigurationToAPI(a.Configuration),
+		})
+	}
+	return out
+}
+
+func mapDTOConfigurationToAPI(configuration []gocd.ConfigProperty) []v1alpha1.KeyValue {
+	if len(configuration) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.KeyValue, 0, len(configuration))
+	for _, c := range configuration {
+		out = append(out, v1alpha1.KeyValue{
+			Key:            c.Key,
+			Value:          c.Value,
+			EncryptedValue: c.EncryptedValue,
+		})
+	}
+	return out
+}
+
+func mapDTOJobTasksToAPI(tasks []gocd.PipelineConfigStageJobsTask) []v1alpha1.TaskWithCancel {
+	if len(tasks) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.TaskWithCancel, 0, len(tasks))
+	for _, t := range tasks {
+		task := v1alpha1.TaskWithCancel{Type: v1alpha1.TaskType(t.Type)}
+		switch a := t.Attributes.(type) {
+		case *gocd.PipelineConfigStageJobsTaskAttributesExec:
+			task.ExecAttributes = &v1alpha1.TaskExecAttributesWithCancel{
+				TaskExecAttributes: mapDTOJobTaskExecToAPI(a),
+				OnCancel:           mapDTOJobTaskToAPI(a.OnCancel),
+			}
+		case *gocd.PipelineConfigStageJobsTaskAttributesAnt:
+			task.AntAttributes = &v1alpha1.TaskAntAttributesWithCancel{
+				TaskAntAttributes: mapDTOJobTaskAntToAPI(a),
+				OnCancel:          mapDTOJobTaskToAPI(a.OnCancel),
+			}
+		case *gocd.PipelineConfigStageJobsTaskAttributesNant:
+			task.NantAttributes = &v1alpha1.TaskNantAttributesWithCancel{
+				TaskNantAttributes: mapDTOJobTaskNantToAPI(a),
+				OnCancel:           mapDTOJobTaskToAPI(a.OnCancel),
+			}
+		case *gocd.PipelineConfigStageJobsTaskAttributesRake:
+			task.RakeAttributes = &v1alpha1.TaskRakeAttributesWithCancel{
+				TaskRakeAttributes: mapDTOJobTaskRakeToAPI(a),
+				OnCancel:           mapDTOJobTaskToAPI(a.OnCancel),
+			}
+		case *gocd.PipelineConfigStageJobsTaskAttributesFetch:
+			task.FetchAttributes = &v1alpha1.TaskFetchAttributesWithCancel{
+				TaskFetchAttributes: mapDTOJobTaskFetchToAPI(a),
+				OnCancel:            mapDTOJobTaskToAPI(a.OnCancel),
+			}
+		case *gocd.PipelineConfigStageJobsTaskAttributesPluggable:
+			task.PluggableAttributes = &v1alpha1.TaskPluggableAttributesWithCancel{
+				TaskPluggableAttributes: mapDTOJobTaskPluggableToAPI(a),
+				OnCancel:                mapDTOJobTaskToAPI(a.OnCancel),
+			}
+		}
+		out = append(out, task)
+	}
+	return out
+}
+
+// mapDTOJobTaskToAPI maps an on_cancel task, which cannot have an on_cancel
+// task of its own.
+func mapDTOJobTaskToAPI(t *gocd.PipelineConfigStageJobsTask) *v1alpha1.Task {
+	if t == nil {
+		return nil
+	}
+	out := &v1alpha1.Task{Type: v1alpha1.TaskType(t.Type)}
+	switch a := t.Attributes.(type) {
+	case *gocd.PipelineConfigStageJobsTaskAttributesExec:
+		attr := mapDTOJobTaskExecToAPI(a)
+		out.ExecAttributes = &attr
+	case *gocd.PipelineConfigStageJobsTaskAttributesAnt:
+		attr := mapDTOJobTaskAntToAPI(a)
+		out.AntAttributes = &attr
+	case *gocd.PipelineConfigStageJobsTaskAttributesNant:
+		attr := mapDTOJobTaskNantToAPI(a)
+		out.NantAttributes = &attr
+	case *gocd.PipelineConfigStageJobsTaskAttributesRake:
+		attr := mapDTOJobTaskRakeToAPI(a)
+		out.RakeAttributes = &attr
+	case *gocd.PipelineConfigStageJobsTaskAttributesFetch:
+		attr := mapDTOJobTaskFetchToAPI(a)
+		out.FetchAttributes = &attr
+	case *gocd.PipelineConfigStageJobsTaskAttributesPluggable:
+		attr := mapDTOJobTaskPluggableToAPI(a)
+		out.PluggableAttributes = &attr
+	}
+	return out
+}
+
+func mapDTOJobTaskExecToAPI(a *gocd.PipelineConfigStageJobsTaskAttributesExec) v1alpha1.TaskExecAttributes {
+	return v1alpha1.TaskExecAttributes{
+		RunIf:            mapDTOJobTaskRunIfToAPI(a.RunIf),
+		Command:          a.Command,
+		Arguments:        a.Arguments,
+		WorkingDirectory: a.WorkingDirectory,
+	}
+}
+
+func mapDTOJobTaskAntToAPI(a *gocd.PipelineConfigStageJobsTaskAttributesAnt) v1alpha1.TaskAntAttributes {
+	return v1alpha1.TaskAntAttributes{
+		RunIf:            mapDTOJobTaskRunIfToAPI(a.RunIf),
+		BuildFile:        a.BuildFile,
+		Target:           a.Target,
+		WorkingDirectory: a.WorkingDirectory,
+	}
+}
+
+func mapDTOJobTaskNantToAPI(a *gocd.PipelineConfigStageJobsTaskAttributesNant) v1alpha1.TaskNantAttributes {
+	return v1alpha1.TaskNantAttributes{
+		RunIf:            mapDTOJobTaskRunIfToAPI(a.RunIf),
+		BuildFile:        a.BuildFile,
+		Target:           a.Target,
+		NantPath:         a.NantPath,
+		WorkingDirectory: a.WorkingDirectory,
+	}
+}
+
+func mapDTOJobTaskRakeToAPI(a *gocd.PipelineConfigStageJobsTaskAttributesRake) v1alpha1.TaskRakeAttributes {
+	return v1alpha1.TaskRakeAttributes{
+		RunIf:            mapDTOJobTaskRunIfToAPI(a.RunIf),
+		BuildFile:        a.BuildFile,
+		Target:           a.Target,
+		WorkingDirectory: a.WorkingDirectory,
+	}
+}
+
+func mapDTOJobTaskFetchToAPI(a *gocd.PipelineConfigStageJobsTaskAttributesFetch) v1alpha1.TaskFetchAttributes {
+	return v1alpha1.TaskFetchAttributes{
+		ArtifactOrigin: v1alpha1.TaskFetchAttributesArtifactOrigin(a.ArtifactOrigin),
+		RunIf:          mapDTOJobTaskRunIfToAPI(a.RunIf),
+		Pipeline:       a.Pipeline,
+		Stage:          a.Stage,
+		Job:            a.Job,
+		Source:         a.Source,
+		IsSourceAFile:  a.IsSourceAFile,
+		Destination:    a.Destination,
+		ArtifactID:     a.ArtifactID,
+		Configuration:  mapDTOConfigurationToAPI(a.Configuration),
+	}
+}
+
+func mapDTOJobTaskPluggableToAPI(a *gocd.PipelineConfigStageJobsTaskAttributesPluggable) v1alpha1.TaskPluggableAttributes {
+	return v1alpha1.TaskPluggableAttributes{
+		RunIf: mapDTOJobTaskRunIfToAPI(a.RunIf),
+		PluginConfiguration: v1alpha1.TaskPluggableAttributesPluginConfiguration{
+			ID:      a.PluginConfiguration.ID,
+			Version: a.PluginConfiguration.Version,
+		},
+		Configuration: mapDTOConfigurationToAPI(a.Configuration),
+	}
+}
+
+func mapDTOJobTaskRunIfToAPI(runIf []gocd.RunIfType) []v1alpha1.TaskAttributesRunIfTypes {
+	if len(runIf) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.TaskAttributesRunIfTypes, 0, len(runIf))
+	for _, r := range runIf {
+		out = append(out, v1alpha1.TaskAttributesRunIfTypes(r))
+	}
+	return out
+}
+
+func mapDTOMaterialsToAPI(materials []gocd.PipelineConfigMaterial) []v1alpha1.Material {
+	if len(materials) == 0 {
+		return nil
+	}
+	out := make([]v1alpha1.Material, 0, len(materials))
+	for _, m := range materials {
+		mat := v1alpha1.Material{Type: v1alpha1.MaterialType(m.Type)}
+		switch a := m.Attributes.(type) {
+		case *gocd.PipelineConfigMaterialAttributesGit:
+			mat.GitAttributes = &v1alpha1.MaterialAttributesGit{
+				Name:            ptr.Deref(a.Name),
+				URL:             ptr.Deref(a.URL),
+				Branch:          ptr.Deref(a.Branch),
+				Username:        ptr.Deref(a.Username),
+				Password:        ptr.Deref(a.Password),
+				Destination:     ptr.Deref(a.Destination),
+				AutoUpdate:      a.AutoUpdate,
+				Filter:          mapDTOFilterToAPI(a.Filter),
+				InvertFilter:    a.InvertFilter,
+				SubmoduleFolder: ptr.Deref(a.SubmoduleFolder),
+				ShallowClone:    a.ShallowClone,
+			}
+		case *gocd.PipelineConfigMaterialAttributesSvn:
+			mat.SvnAttributes = &v1alpha1.MaterialAttributesSvn{
+				Name:              ptr.Deref(a.Name),
+				URL:               ptr.Deref(a.URL),
+				Username:          ptr.Deref(a.Username),
+				Password:          ptr.Deref(a.Password),
+				EncryptedPassword: ptr.Deref(a.EncryptedPassword),
+				Destination:       ptr.Deref(a.Destination),
+				Filter:            mapDTOFilterToAPI(a.Filter),
+				InvertFilter:      a.InvertFilter,
+				AutoUpdate:        a.AutoUpdate,
+				CheckExternals:    a.CheckExternals,
+			}
+		case *gocd.PipelineConfigMaterialAttributesHg:
+			mat.HgAttributes = &v1alpha1.MaterialAttributesHg{
+				Name:              ptr.Deref(a.Name),
+				URL:               ptr.Deref(a.URL),
+				Branch:            ptr.Deref(a.Branch),
+				Username:          ptr.Deref(a.Username),
+				Password:          ptr.Deref(a.Password),
+				EncryptedPassword: ptr.Deref(a.EncryptedPassword),
+				Destination:       ptr.Deref(a.Destination),
+				Filter:            mapDTOFilterToAPI(a.Filter),
+				InvertFilter:      a.InvertFilter,
+				AutoUpdate:        a.AutoUpdate,
+			}
+		case *gocd.PipelineConfigMaterialAttributesP4:
+			mat.P4Attributes = &v1alpha1.MaterialAttributesP4{
+				Name:              ptr.Deref(a.Name),
+				Port:              ptr.Deref(a.Port),
+				UseTickets:        a.UseTickets,
+				View:              ptr.Deref(a.View),
+				Username:          ptr.Deref(a.Username),
+				Password:          ptr.Deref(a.Password),
+				EncryptedPassword: ptr.Deref(a.EncryptedPassword),
+				Destination:       ptr.Deref(a.Destination),
+				Filter:            mapDTOFilterToAPI(a.Filter),
+				InvertFilter:      a.InvertFilter,
+				AutoUpdate:        a.AutoUpdate,
+			}
+		case *gocd.PipelineConfigMaterialAttributesTfs:
+			mat.TfsAttributes = &v1alpha1.MaterialAttributesTfs{
+				Name:              ptr.Deref(a.Name),
+				URL:               ptr.Deref(a.URL),
+				ProjectPath:       ptr.Deref(a.ProjectPath),
+				Domain:            ptr.Deref(a.Domain),
+				Username:          ptr.Deref(a.Username),
+				Password:          ptr.Deref(a.Password),
+				EncryptedPassword: ptr.Deref(a.EncryptedPassword),
+				Destination:       ptr.Deref(a.Destination),
+				AutoUpdate:        a.AutoUpdate,
+				Filter:            mapDTOFilterToAPI(a.Filter),
+				InvertFilter:      a.InvertFilter,
+			}
+		case *gocd.PipelineConfigMaterialAttributesDependency:
+			mat.DependencyAttributes = &v1alpha1.MaterialAttributesDependency{
+				Name:                ptr.Deref(a.Name),
+				Pipeline:            ptr.Deref(a.Pipeline),
+				Stage:               ptr.Deref(a.Stage),
+				AutoUpdate:          a.AutoUpdate,
+				IgnoreForScheduling: a.IgnoreForScheduling,
+			}
+		case *gocd.PipelineConfigMaterialAttributesPackage:
+			mat.PackageAttributes = &v1alpha1.MaterialAttributesPackage{
+				Ref: a.Ref,
+			}
+		case *gocd.PipelineConfigMaterialAttributesPlugin:
+			mat.PluginAttributes = &v1alpha1.MaterialAttributesPlugin{
+				Ref:          ptr.Deref(a.Ref),
+				Destination:  ptr.Deref(a.Destination),
+				Filter:       mapDTOFilterToAPI(a.Filter),
+				InvertFilter: a.InvertFilter,
+			}
+		}
+		out = append(out, mat)
+	}
+	return out
+}
+
+func mapDTOFilterToAPI(f *gocd.PipelineConfigMaterialFilter) v1alpha1.Filter {
+	if f == nil {
+		return v1alpha1.Filter{}
+	}
+	return v1alpha1.Filter{
+		Ignore:   f.Ignore,
+		Includes: f.Includes,
+	}
+}
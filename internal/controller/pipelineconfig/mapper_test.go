@@ -0,0 +1,552 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelineconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
+)
+
+const (
+	roundTrips      = 200
+	secretName      = "secure-variables"
+	secretNamespace = "ci"
+)
+
+// gen generates random but valid pipeline configs. Values the mappers
+// normalise, e.g. an empty lock behavior that becomes "none", are only
+// generated in their normalised form.
+type gen struct {
+	r       *rand.Rand
+	secrets map[string][]byte
+}
+
+func newGen(seed uint64) *gen {
+	return &gen{r: rand.New(rand.NewPCG(seed, seed)), secrets: map[string][]byte{}}
+}
+
+func (g *gen) bool() bool { return g.r.IntN(2) == 0 }
+
+func (g *gen) str() string {
+	if g.r.IntN(4) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("s%d", g.r.IntN(1000))
+}
+
+// nonEmpty returns a string the mappers do not turn into nil.
+func (g *gen) nonEmpty() string { return fmt.Sprintf("v%d", g.r.IntN(1000)) }
+
+func (g *gen) strs() []string {
+	n := g.r.IntN(3)
+	if n == 0 {
+		return nil
+	}
+	out := make([]string, n)
+	for i := range out {
+		out[i] = g.nonEmpty()
+	}
+	return out
+}
+
+func pick[T any](g *gen, vs ...T) T { return vs[g.r.IntN(len(vs))] }
+
+func (g *gen) keyValues() []v1alpha1.KeyValue {
+	var out []v1alpha1.KeyValue
+	for range g.r.IntN(3) {
+		out = append(out, v1alpha1.KeyValue{Key: g.nonEmpty(), Value: g.str()})
+	}
+	return out
+}
+
+func (g *gen) filter() v1alpha1.Filter {
+	return v1alpha1.Filter{Ignore: g.strs(), Includes: g.strs()}
+}
+
+func (g *gen) envVars(scope ...string) []v1alpha1.EnvironmentVariable {
+	var out []v1alpha1.EnvironmentVariable
+	for i := range g.r.IntN(3) {
+		name := fmt.Sprintf("VAR_%d", i)
+		if g.bool() {
+			out = append(out, v1alpha1.EnvironmentVariable{Name: name, Value: g.str()})
+			continue
+		}
+		key := secretKeyFor(scope, name)
+		g.secrets[key] = []byte(g.nonEmpty())
+		out = append(out, v1alpha1.EnvironmentVariable{Name: name, ValueFrom: secretRef(key)})
+	}
+	return out
+}
+
+func secretKeyFor(scope []string, name string) string {
+	return strings.Join(append(append([]string{}, scope...), name), ".")
+}
+
+func secretRef(key string) *v1alpha1.ValueSource {
+	return &v1alpha1.ValueSource{SecretKeyRef: &xpv1.SecretKeySelector{
+		SecretReference: xpv1.SecretReference{Name: secretName, Namespace: secretNamespace},
+		Key:             key,
+	}}
+}
+
+func (g *gen) runIf() []v1alpha1.TaskAttributesRunIfTypes {
+	var out []v1alpha1.TaskAttributesRunIfTypes
+	for range g.r.IntN(3) {
+		out = append(out, pick[v1alpha1.TaskAttributesRunIfTypes](g,
+			v1alpha1.TaskExecAttributesRunIfTypesPassed, v1alpha1.TaskExecAttributesRunIfTypesFailed, v1alpha1.TaskExecAttributesRunIfTypesAny))
+	}
+	return out
+}
+
+func (g *gen) exec() v1alpha1.TaskExecAttributes {
+	a := v1alpha1.TaskExecAttributes{RunIf: g.runIf(), Command: g.nonEmpty(), Arguments: g.strs()}
+	if g.bool() {
+		wd := g.str()
+		a.WorkingDirectory = &wd
+	}
+	return a
+}
+
+func (g *gen) ant() v1alpha1.TaskAntAttributes {
+	return v1alpha1.TaskAntAttributes{RunIf: g.runIf(), BuildFile: g.str(), Target: g.str(), WorkingDirectory: g.str()}
+}
+
+func (g *gen) nant() v1alpha1.TaskNantAttributes {
+	return v1alpha1.TaskNantAttributes{RunIf: g.runIf(), BuildFile: g.str(), Target: g.str(), NantPath: g.str(), WorkingDirectory: g.str()}
+}
+
+func (g *gen) rake() v1alpha1.TaskRakeAttributes {
+	return v1alpha1.TaskRakeAttributes{RunIf: g.runIf(), BuildFile: g.str(), Target: g.str(), WorkingDirectory: g.str()}
+}
+
+func (g *gen) fetch() v1alpha1.TaskFetchAttributes {
+	return v1alpha1.TaskFetchAttributes{
+		ArtifactOrigin: pick(g, v1alpha1.TaskFetchAttributesArtifactOriginGoCD, v1alpha1.TaskFetchAttributesArtifactOriginExternal),
+		RunIf:          g.runIf(),
+		Pipeline:       g.str(),
+		Stage:          g.str(),
+		Job:            g.str(),
+		Source:         g.str(),
+		IsSourceAFile:  g.bool(),
+		Destination:    g.str(),
+		ArtifactID:     g.str(),
+		Configuration:  g.keyValues(),
+	}
+}
+
+func (g *gen) pluggable() v1alpha1.TaskPluggableAttributes {
+	return v1alpha1.TaskPluggableAttributes{
+		RunIf:               g.runIf(),
+		PluginConfiguration: v1alpha1.TaskPluggableAttributesPluginConfiguration{ID: g.str(), Version: g.str()},
+		Configuration:       g.keyValues(),
+	}
+}
+
+var taskTypes = []v1alpha1.TaskType{
+	v1alpha1.TaskTypeExec, v1alpha1.TaskTypeAnt, v1alpha1.TaskTypeNant,
+	v1alpha1.TaskTypeRake, v1alpha1.TaskTypeFetch, v1alpha1.TaskTypePluggable,
+}
+
+func (g *gen) cancelTask() *v1alpha1.Task {
+	if g.bool() {
+		return nil
+	}
+	t := &v1alpha1.Task{Type: pick(g, taskTypes...)}
+	switch t.Type {
+	case v1alpha1.TaskTypeExec:
+		a := g.exec()
+		t.ExecAttributes = &a
+	case v1alpha1.TaskTypeAnt:
+		a := g.ant()
+		t.AntAttributes = &a
+	case v1alpha1.TaskTypeNant:
+		a := g.nant()
+		t.NantAttributes = &a
+	case v1alpha1.TaskTypeRake:
+		a := g.rake()
+		t.RakeAttributes = &a
+	case v1alpha1.TaskTypeFetch:
+		a := g.fetch()
+		t.FetchAttributes = &a
+	case v1alpha1.TaskTypePluggable:
+		a := g.pluggable()
+		t.PluggableAttributes = &a
+	}
+	return t
+}
+
+func (g *gen) tasks() []v1alpha1.TaskWithCancel {
+	var out []v1alpha1.TaskWithCancel
+	for range g.r.IntN(4) {
+		t := v1alpha1.TaskWithCancel{Type: pick(g, taskTypes...)}
+		switch t.Type {
+		case v1alpha1.TaskTypeExec:
+			t.ExecAttributes = &v1alpha1.TaskExecAttributesWithCancel{TaskExecAttributes: g.exec(), OnCancel: g.cancelTask()}
+		case v1alpha1.TaskTypeAnt:
+			t.AntAttributes = &v1alpha1.TaskAntAttributesWithCancel{TaskAntAttributes: g.ant(), OnCancel: g.cancelTask()}
+		case v1alpha1.TaskTypeNant:
+			t.NantAttributes = &v1alpha1.TaskNantAttributesWithCancel{TaskNantAttributes: g.nant(), OnCancel: g.cancelTask()}
+		case v1alpha1.TaskTypeRake:
+			t.RakeAttributes = &v1alpha1.TaskRakeAttributesWithCancel{TaskRakeAttributes: g.rake(), OnCancel: g.cancelTask()}
+		case v1alpha1.TaskTypeFetch:
+			t.FetchAttributes = &v1alpha1.TaskFetchAttributesWithCancel{TaskFetchAttributes: g.fetch(), OnCancel: g.cancelTask()}
+		case v1alpha1.TaskTypePluggable:
+			t.PluggableAttributes = &v1alpha1.TaskPluggableAttributesWithCancel{TaskPluggableAttributes: g.pluggable(), OnCancel: g.cancelTask()}
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func (g *gen) artifacts() []v1alpha1.JobArtifact {
+	var out []v1alpha1.JobArtifact
+	for range g.r.IntN(3) {
+		a := v1alpha1.JobArtifact{
+			Type:          pick[v1alpha1.JobArtifactType](g, v1alpha1.JobArtifactTypeBuild, v1alpha1.JobArtifactTypeTest, v1alpha1.JobArtifactTypeExternal),
+			Source:        g.str(),
+			ID:            g.str(),
+			Configuration: g.keyValues(),
+		}
+		if g.bool() {
+			d := g.str()
+			a.Destination = &d
+		}
+		if g.bool() {
+			s := g.str()
+			a.StoreID = &s
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func (g *gen) intOrString() intstr.IntOrString {
+	switch g.r.IntN(3) {
+	case 0:
+		return intstr.IntOrString{}
+	case 1:
+		return intstr.FromInt32(g.r.Int32N(10) + 1)
+	default:
+		return intstr.FromString(g.nonEmpty())
+	}
+}
+
+func (g *gen) jobs(stage string) []v1alpha1.Job {
+	var out []v1alpha1.Job
+	for i := range g.r.IntN(3) {
+		name := fmt.Sprintf("job%d", i)
+		var tabs []v1alpha1.JobTab
+		for range g.r.IntN(2) {
+			tabs = append(tabs, v1alpha1.JobTab{Name: g.str(), Path: g.str()})
+		}
+		out = append(out, v1alpha1.Job{
+			Name:                 name,
+			RunInstanceCount:     g.intOrString(),
+			Timeout:              g.intOrString(),
+			EnvironmentVariables: g.envVars(stage, name),
+			Resources:            g.strs(),
+			Tasks:                g.tasks(),
+			Tabs:                 tabs,
+			Artifacts:            g.artifacts(),
+			ElasticProfileID:     g.str(),
+		})
+	}
+	return out
+}
+
+func (g *gen) stages() []v1alpha1.Stage {
+	var out []v1alpha1.Stage
+	for i := range g.r.IntN(3) {
+		name := fmt.Sprintf("stage%d", i)
+		out = append(out, v1alpha1.Stage{
+			Name:                  name,
+			FetchMaterials:        g.bool(),
+			CleanWorkingDir:       g.bool(),
+			NeverCleanupArtifacts: g.bool(),
+			Approval: v1alpha1.StageApproval{
+				Type:               pick(g, v1alpha1.StageApprovalTypeSuccess, v1alpha1.StageApprovalTypeManual),
+				AllowOnlyOnSuccess: g.bool(),
+				Authorization:      v1alpha1.StageApprovalAuthorization{Users: g.strs(), Roles: g.strs()},
+			},
+			EnvironmentVariables: g.envVars(name),
+			Jobs:                 g.jobs(name),
+		})
+	}
+	return out
+}
+
+func (g *gen) materials() []v1alpha1.Material {
+	var out []v1alpha1.Material
+	for range g.r.IntN(4) {
+		m := v1alpha1.Material{Type: pick(g,
+			v1alpha1.MaterialTypeGit, v1alpha1.MaterialTypeSvn, v1alpha1.MaterialTypeHg, v1alpha1.MaterialTypeP4,
+			v1alpha1.MaterialTypeTfs, v1alpha1.MaterialTypeDependency, v1alpha1.MaterialTypePackage, v1alpha1.MaterialTypePlugin)}
+		switch m.Type {
+		case v1alpha1.MaterialTypeGit:
+			m.GitAttributes = &v1alpha1.MaterialAttributesGit{
+				Name: g.str(), URL: g.str(), Branch: g.str(), Username: g.str(), Password: g.str(), Destination: g.str(),
+				AutoUpdate: g.bool(), Filter: g.filter(), InvertFilter: g.bool(), SubmoduleFolder: g.str(), ShallowClone: g.bool(),
+			}
+		case v1alpha1.MaterialTypeSvn:
+			m.SvnAttributes = &v1alpha1.MaterialAttributesSvn{
+				Name: g.str(), URL: g.str(), Username: g.str(), Password: g.str(), EncryptedPassword: g.str(), Destination: g.str(),
+				Filter: g.filter(), InvertFilter: g.bool(), AutoUpdate: g.bool(), CheckExternals: g.bool(),
+			}
+		case v1alpha1.MaterialTypeHg:
+			m.HgAttributes = &v1alpha1.MaterialAttributesHg{
+				Name: g.str(), URL: g.str(), Branch: g.str(), Username: g.str(), Password: g.str(), EncryptedPassword: g.str(),
+				Destination: g.str(), Filter: g.filter(), InvertFilter: g.bool(), AutoUpdate: g.bool(),
+			}
+		case v1alpha1.MaterialTypeP4:
+			m.P4Attributes = &v1alpha1.MaterialAttributesP4{
+				Name: g.str(), Port: g.str(), UseTickets: g.bool(), View: g.str(), Username: g.str(), Password: g.str(),
+				EncryptedPassword: g.str(), Destination: g.str(), Filter: g.filter(), InvertFilter: g.bool(), AutoUpdate: g.bool(),
+			}
+		case v1alpha1.MaterialTypeTfs:
+			m.TfsAttributes = &v1alpha1.MaterialAttributesTfs{
+				Name: g.str(), URL: g.str(), ProjectPath: g.str(), Domain: g.str(), Username: g.str(), Password: g.str(),
+				EncryptedPassword: g.str(), Destination: g.str(), AutoUpdate: g.bool(), Filter: g.filter(), InvertFilter: g.bool(),
+			}
+		case v1alpha1.MaterialTypeDependency:
+			m.DependencyAttributes = &v1alpha1.MaterialAttributesDependency{
+				Name: g.str(), Pipeline: g.str(), Stage: g.str(), AutoUpdate: g.bool(), IgnoreForScheduling: g.bool(),
+			}
+		case v1alpha1.MaterialTypePackage:
+			var ref *string
+			if g.bool() {
+				r := g.str()
+				ref = &r
+			}
+			m.PackageAttributes = &v1alpha1.MaterialAttributesPackage{Ref: ref}
+		case v1alpha1.MaterialTypePlugin:
+			m.PluginAttributes = &v1alpha1.MaterialAttributesPlugin{
+				Ref: g.str(), Destination: g.str(), Filter: g.filter(), InvertFilter: g.bool(),
+			}
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func (g *gen) pipelineConfig() v1alpha1.PipelineConfigForProvider {
+	pc := v1alpha1.PipelineConfigForProvider{
+		Group:                g.str(),
+		LabelTemplate:        g.str(),
+		LockBehavior:         pick(g, v1alpha1.LockBehaviorLockOnFailure, v1alpha1.LockBehaviorUnlockWhenFinished, v1alpha1.LockBehaviorNone),
+		Name:                 g.nonEmpty(),
+		Template:             g.str(),
+		Origin:               v1alpha1.Origin{Type: pick(g, v1alpha1.OriginTypeGoCD, v1alpha1.OriginTypeConfigRepo), ID: g.str()},
+		EnvironmentVariables: g.envVars(),
+		Materials:            g.materials(),
+		Stages:               g.stages(),
+	}
+	for i := range g.r.IntN(3) {
+		pc.Parameters = append(pc.Parameters, v1alpha1.Parameter{Name: fmt.Sprintf("p%d", i), Value: ptr.ToPtr(g.str())})
+	}
+	if g.bool() {
+		pc.TrackingTool = v1alpha1.TrackingTool{Type: g.nonEmpty(), Attributes: v1alpha1.TrackingToolAttributes{URLPattern: g.str(), Regex: g.str()}}
+	}
+	if g.bool() {
+		pc.Timer = v1alpha1.Timer{Spec: g.nonEmpty(), OnlyOnChanges: g.bool()}
+	}
+	return pc
+}
+
+// secureFromSecret reads secure variables back from the Secret the generator
+// wrote their values to.
+func secureFromSecret(scope []string, name string) *v1alpha1.ValueSource {
+	return secretRef(secretKeyFor(scope, name))
+}
+
+func asJSON(t *testing.T, v any) any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestMapperRoundTripFromAPI(t *testing.T) {
+	for seed := range uint64(roundTrips) {
+		g := newGen(seed)
+		want := g.pipelineConfig()
+		kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: secretNamespace},
+			Data:       g.secrets,
+		}).Build()
+
+		dto, err := mapAPIToDtoPipelineConfig(context.Background(), kube, want)
+		if err != nil {
+			t.Fatalf("seed %d: mapAPIToDtoPipelineConfig: %v", seed, err)
+		}
+		got := MapDTOToAPIPipelineConfig(dto, secureFromSecret)
+		if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("seed %d: API -> DTO -> API: -want, +got:\n%s", seed, diff)
+		}
+	}
+}
+
+func TestMapperRoundTripFromDTO(t *testing.T) {
+	for seed := range uint64(roundTrips) {
+		g := newGen(seed)
+		api := g.pipelineConfig()
+		kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: secretNamespace},
+			Data:       g.secrets,
+		}).Build()
+		want, err := mapAPIToDtoPipelineConfig(context.Background(), kube, api)
+		if err != nil {
+			t.Fatalf("seed %d: mapAPIToDtoPipelineConfig: %v", seed, err)
+		}
+
+		got, err := mapAPIToDtoPipelineConfig(context.Background(), kube, MapDTOToAPIPipelineConfig(want, secureFromSecret))
+		if err != nil {
+			t.Fatalf("seed %d: mapAPIToDtoPipelineConfig: %v", seed, err)
+		}
+		// PipelineConfig.Equal ignores fields GoCD never returns, so compare
+		// what would be sent to the server instead.
+		if diff := cmp.Diff(asJSON(t, want), asJSON(t, got), cmpopts.EquateEmpty()); diff != "" {
+			t.Fatalf("seed %d: DTO -> API -> DTO: -want, +got:\n%s", seed, diff)
+		}
+	}
+}
+
+func TestMapDTOToAPISecureVariables(t *testing.T) {
+	pc := &gocd.PipelineConfig{
+		EnvironmentVariables: []gocd.EnvironmentVariable{
+			{Name: "PLAIN", Value: "visible"},
+			{Name: "SECRET", EncryptedValue: "AES:abc", Secure: true},
+		},
+	}
+
+	got := MapDTOToAPIPipelineConfig(pc, nil).EnvironmentVariables
+	want := []v1alpha1.EnvironmentVariable{{Name: "PLAIN", Value: "visible"}, {Name: "SECRET"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("without a source, secure variables must have no value: -want, +got:\n%s", diff)
+	}
+
+	got = MapDTOToAPIPipelineConfig(pc, secureFromSecret).EnvironmentVariables
+	want[1].ValueFrom = secretRef("SECRET")
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("secure variables must be read from their source: -want, +got:\n%s", diff)
+	}
+}
+
+func TestMapAPIEnvFrom(t *testing.T) {
+	kube := fake.NewClientBuilder().WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "ci"},
+			Data:       map[string][]byte{"TOKEN": []byte("hunter2"), "USER": []byte("bot")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "ci"},
+			Data:       map[string]string{"REGISTRY": "registry.example.com", "USER": "anonymous", "REGION": "eu"},
+		},
+	).Build()
+	envFrom := []v1alpha1.EnvFromSource{
+		{ConfigMapRef: &v1alpha1.ConfigMapReference{Name: "defaults", Namespace: "ci"}},
+		{SecretRef: &xpv1.SecretReference{Name: "deploy", Namespace: "ci"}},
+		{Prefix: "CFG_", Secure: true, ConfigMapRef: &v1alpha1.ConfigMapReference{Name: "defaults", Namespace: "ci"}},
+	}
+	variables := []v1alpha1.EnvironmentVariable{{Name: "REGION", Value: "us"}}
+
+	got, err := mapAPIEnvironmentVariablesToDTO(context.Background(), kube, envFrom, variables)
+	if err != nil {
+		t.Fatalf("mapAPIEnvironmentVariablesToDTO(...): %v", err)
+	}
+	want := []gocd.EnvironmentVariable{
+		{Name: "CFG_REGION", Value: "eu", Secure: true},
+		{Name: "CFG_REGISTRY", Value: "registry.example.com", Secure: true},
+		{Name: "CFG_USER", Value: "anonymous", Secure: true},
+		{Name: "REGISTRY", Value: "registry.example.com"},
+		{Name: "TOKEN", Value: "hunter2", Secure: true},
+		{Name: "USER", Value: "bot", Secure: true},
+		{Name: "REGION", Value: "us"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mapAPIEnvironmentVariablesToDTO(...): -want, +got:\n%s", diff)
+	}
+
+	envFrom = append(envFrom, v1alpha1.EnvFromSource{SecretRef: &xpv1.SecretReference{Name: "missing", Namespace: "ci"}})
+	if _, err := mapAPIEnvironmentVariablesToDTO(context.Background(), kube, envFrom, nil); err == nil {
+		t.Errorf("mapAPIEnvironmentVariablesToDTO(...): want an error for a missing Secret")
+	}
+}
+
+func TestMapAPIParameters(t *testing.T) {
+	kube := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "ci"},
+			Data:       map[string]string{"registry": "registry.example.com"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "ci"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		},
+	).Build()
+	ref := func(key string) *v1alpha1.ValueSource {
+		return &v1alpha1.ValueSource{ConfigMapKeyRef: &v1alpha1.ConfigMapKeySelector{Name: "platform", Namespace: "ci", Key: key}}
+	}
+	params := []v1alpha1.Parameter{
+		{Name: "DEPLOY_TARGET", Value: ptr.ToPtr("production")},
+		{Name: "SUFFIX", Value: ptr.ToPtr("")},
+		{Name: "REGISTRY", ValueFrom: ref("registry")},
+		{Name: "TOKEN", ValueFrom: &v1alpha1.ValueSource{SecretKeyRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Name: "platform", Namespace: "ci"}, Key: "token",
+		}}},
+	}
+
+	got, err := mapAPIParametersToDTO(context.Background(), kube, params)
+	if err != nil {
+		t.Fatalf("mapAPIParametersToDTO(...): %v", err)
+	}
+	want := []gocd.PipelineConfigParameter{
+		{Name: "DEPLOY_TARGET", Value: "production"},
+		{Name: "SUFFIX", Value: ""},
+		{Name: "REGISTRY", Value: "registry.example.com"},
+		{Name: "TOKEN", Value: "s3cr3t"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mapAPIParametersToDTO(...): -want, +got:\n%s", diff)
+	}
+
+	params = append(params, v1alpha1.Parameter{Name: "MISSING", ValueFrom: ref("missing")})
+	if _, err := mapAPIParametersToDTO(context.Background(), kube, params); err == nil {
+		t.Errorf("mapAPIParametersToDTO(...): want an error for a missing key")
+	}
+}
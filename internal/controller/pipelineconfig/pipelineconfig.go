@@ -18,15 +18,13 @@ package pipelineconfig
 
 import (
 	"context"
-	"encoding/json"
-	"reflect"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/marquesgui/provider-gocd/pkg/gocd"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -43,9 +41,17 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
+	"github.com/marquesgui/provider-gocd/internal/clients"
 	"github.com/marquesgui/provider-gocd/internal/controller/helper"
+	"github.com/marquesgui/provider-gocd/internal/drift"
+	"github.com/marquesgui/provider-gocd/internal/dryrun"
 	"github.com/marquesgui/provider-gocd/internal/features"
+	"github.com/marquesgui/provider-gocd/internal/ignore"
+	"github.com/marquesgui/provider-gocd/internal/keyring"
+	"github.com/marquesgui/provider-gocd/internal/tracing"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 )
 
 const (
@@ -53,25 +59,18 @@ const (
 	errTrackPCUsage      = "cannot track ProviderConfig usage"
 	errGetPC             = "cannot get ProviderConfig"
 	errGetCreds          = "cannot get credentials"
+	errGetHashKeys       = "cannot get hash keys"
 	errNewClient         = "cannot create new Service"
 )
 
-var newService = func(creds []byte) (any, error) {
-	cfg, err := apisv1alpha1.ParseGocdProviderConfig(creds)
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot parse gocd provider config")
-	}
-	c, err := gocd.New(gocd.Config{
-		BaseURL:  cfg.BaseURL,
-		Username: cfg.Username,
-		Password: cfg.Password,
-		Token:    cfg.Token,
-		Insecure: cfg.Insecure,
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot create new GoCD client")
-	}
-	return c.PipelineConfigs(), nil
+// newService returns the GoCD service this controller manages.
+var newService = func(gc gocd.Client) any {
+	return gc.PipelineConfigs()
+}
+
+// newEncryption returns the GoCD service encrypting secure values.
+var newEncryption = func(gc gocd.Client) gocd.EncryptionService {
+	return gc.Encryption()
 }
 
 // Setup adds a controller that reconciles PipelineConfig managed resources.
@@ -83,15 +82,22 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	dryRun := o.Features.Enabled(features.EnableDryRun)
 	opts := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
-			kube:         mgr.GetClient(),
-			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
-			newServiceFn: newService,
-		}),
+		managed.WithExternalConnecter(tracing.NewConnector(v1alpha1.PipelineConfigKind, dryrun.NewConnector(&connector{
+			kube:            mgr.GetClient(),
+			usage:           resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients:         clients.DefaultCache,
+			newServiceFn:    newService,
+			newEncryptionFn: newEncryption,
+			keys:            keyring.DefaultSource,
+			drift:           drift.NewReporter(recorder),
+			dryRun:          dryRun,
+		}, dryRun, recorder))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...),
 		managed.WithManagementPolicies(),
 	}
@@ -115,20 +121,31 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.PipelineConfigGroupVersionKind), opts...)
 
+	if err := setupIndexes(context.Background(), mgr); err != nil {
+		return err
+	}
+
+	log := o.Logger.WithValues("controller", name)
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.PipelineConfig{}).
+		For(&v1alpha1.PipelineConfig{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(&corev1.Secret{}, enqueueReferencing(mgr.GetClient(), secretRefsIndex, log)).
+		Watches(&corev1.ConfigMap{}, enqueueReferencing(mgr.GetClient(), configMapRefsIndex, log)).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube         client.Client
-	usage        resource.Tracker
-	newServiceFn func(creds []byte) (any, error)
+	kube            client.Client
+	usage           resource.Tracker
+	clients         *clients.Cache
+	newServiceFn    func(gc gocd.Client) any
+	newEncryptionFn func(gc gocd.Client) gocd.EncryptionService
+	keys            *keyring.Source
+	drift           *drift.Reporter
+	dryRun          bool
 }
 
 // Connect typically produces an ExternalClient by:
@@ -151,22 +168,31 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err := clients.CheckHealth(pc); err != nil {
+		return nil, err
+	}
+
+	creds, err := clients.ExtractCredentials(ctx, c.kube, pc)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(data)
+	gc, err := c.clients.Get(pc, creds)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
+	svc := c.newServiceFn(gc)
+
 	s, ok := svc.(gocd.PipelineConfigsService)
 	if !ok {
 		return nil, errors.New("returned service does not implement gocd.PipelineConfigsService")
 	}
-	return &external{service: s, kube: c.kube}, nil
+	keys, err := c.keys.Keyring(ctx, c.kube)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetHashKeys)
+	}
+	return &external{service: s, encryption: c.newEncryptionFn(gc), keys: keys, kube: newCachingReader(c.kube), drift: c.drift, dryRun: dryrun.Enabled(mg, c.dryRun)}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
@@ -175,8 +201,21 @@ type external struct {
 	// A 'client' used to connect to the external resource API. In practice this
 	// would be something like an AWS SDK client.
 	service gocd.PipelineConfigsService
-	// Kubernetes client
-	kube client.Client
+	// Encrypts the values of secure variables.
+	encryption gocd.EncryptionService
+	// Hashes the values of secure variables.
+	keys *keyring.Keyring
+	// Kubernetes client, caching the Secrets and ConfigMaps read during this
+	// reconcile.
+	kube client.Reader
+	// Reports drift between spec and the observed pipeline.
+	drift *drift.Reporter
+	// Whether the managed resource is in dry run, in which case Observe
+	// neither changes it nor asks GoCD to encrypt values.
+	dryRun bool
+	// The pipeline as observed during this reconcile, which ignored fields are
+	// filled from when it is updated.
+	observed *gocd.PipelineConfig
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -197,16 +236,53 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if got == nil {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+	c.observed = got
 
-	err = updateStatus(pc, got)
-	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(err, "cannot update status")
+	updateStatus(pc, got, c.keys)
+	helper.KeepETag(pc, etag)
+
+	// A dry run late initializes and migrates a copy, which the desired
+	// pipeline is compared from but which is never persisted.
+	target := pc
+	if c.dryRun {
+		target = pc.DeepCopy()
 	}
+	lateInitialized := lateInitialize(&target.Spec.ForProvider, got)
 
-	helper.KeepETag(pc, etag)
-	upToDate, err := isUpToDate(ctx, c.kube, pc, got)
+	migrated, err := migrateStatusHashes(target, got)
 	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot migrate environment variable hashes")
+	}
+	lateInitialized = lateInitialized || migrated
+
+	// Drift is neither decided nor reported while a referenced Secret or
+	// ConfigMap is missing; the update reports the missing object.
+	upToDate := false
+	desired, err := resolve(ctx, c.kube, target.Spec.ForProvider)
+	switch {
+	case k8serrors.IsNotFound(errors.Cause(err)):
+	case err != nil:
 		return managed.ExternalObservation{}, errors.Wrap(err, "cannot determine if pipeline config is up to date")
+	default:
+		if c.dryRun {
+			sealSecureVariables(c.keys, desired, getSecureValues(target))
+		} else {
+			// New secure values are encrypted here rather than by Update, so
+			// that their record is persisted with the late initialized spec
+			// before it is applied.
+			recorded, err := c.encryptSecureValues(ctx, pc, desired)
+			if err != nil {
+				return managed.ExternalObservation{}, errors.Wrap(err, "cannot encrypt secure variables")
+			}
+			lateInitialized = lateInitialized || recorded
+		}
+		if err := ignore.Apply(desired, got, pc.Spec.IgnoreChanges); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot ignore changes")
+		}
+		upToDate = desired.Equal(got)
+		if err := c.reportDrift(pc, desired, got, upToDate); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot compare pipeline config")
+		}
 	}
 
 	if upToDate {
@@ -216,12 +292,40 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: lateInitialized && !c.dryRun,
+		ConnectionDetails:       managed.ConnectionDetails{},
 	}, nil
 }
 
+// reportDrift records the differences between the desired and the observed
+// pipeline in status. The values of parameters read from Secrets are
+// redacted.
+func (c *external) reportDrift(pc *v1alpha1.PipelineConfig, desired, got *gocd.PipelineConfig, upToDate bool) error {
+	var diffs []drift.Difference
+	if !upToDate {
+		var err error
+		if diffs, err = drift.Diff(desired, got, secretParameters(pc.Spec.ForProvider)...); err != nil {
+			return err
+		}
+	}
+	c.drift.Report(pc, &pc.Status.Drift, diffs)
+	return nil
+}
+
+// secretParameters returns the drift paths of the values of the parameters
+// read from Secrets.
+func secretParameters(sp v1alpha1.PipelineConfigForProvider) []string {
+	var out []string
+	for _, p := range sp.Parameters {
+		if p.ValueFrom != nil && p.ValueFrom.SecretKeyRef != nil {
+			out = append(out, "parameters["+p.Name+"].value")
+		}
+	}
+	return out
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.PipelineConfig)
 	if !ok {
@@ -231,30 +335,25 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	name := helper.GetID(cr, cr.Spec.ForProvider.Name)
 	cr.Spec.ForProvider.Name = name
 
-	requestBody, err := mapAPIToDtoPipelineConfig(ctx, c.kube, cr.Spec.ForProvider)
+	requestBody, err := resolve(ctx, c.kube, cr.Spec.ForProvider)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "could not map api to dto")
 	}
+	// Annotations set by Create are persisted.
+	if _, err := c.encryptSecureValues(ctx, cr, requestBody); err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, "cannot encrypt secure variables")
+	}
 
 	out, etag, err := c.service.Create(ctx, requestBody)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, "cannot create pipeline config")
 	}
 
-	hashes, err := calculateHashes(ctx, c.kube, cr.Spec.ForProvider)
-	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, "cannot calculate hashes from spec")
-	}
-	cr.Status.EnvironmentVariableHashes = hashes
-
 	if out != nil {
 		if out.Name != nil {
 			meta.SetExternalName(cr, *out.Name)
 		}
-		err = updateStatus(cr, out)
-		if err != nil {
-			return managed.ExternalCreation{}, errors.Wrap(err, "cannot update status")
-		}
+		updateStatus(cr, out, c.keys)
 	}
 	helper.KeepETag(cr, etag)
 
@@ -271,10 +370,18 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	cr.Spec.ForProvider.Name = meta.GetExternalName(cr)
 
-	requestBody, err := mapAPIToDtoPipelineConfig(ctx, c.kube, cr.Spec.ForProvider)
+	requestBody, err := resolve(ctx, c.kube, cr.Spec.ForProvider)
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot map the api request to dto")
 	}
+	// Observe has recorded the encrypted values, so this only encrypts values
+	// that changed since.
+	if _, err := c.encryptSecureValues(ctx, cr, requestBody); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot encrypt secure variables")
+	}
+	if err := ignore.Apply(requestBody, c.observed, cr.Spec.IgnoreChanges); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot ignore changes")
+	}
 
 	etag := helper.GetETag(cr)
 	out, etag, err := c.service.Update(ctx, etag, requestBody)
@@ -282,17 +389,8 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot update pipeline config")
 	}
 
-	hashes, err := calculateHashes(ctx, c.kube, cr.Spec.ForProvider)
-	if err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot calculate hashes from spec")
-	}
-	cr.Status.EnvironmentVariableHashes = hashes
-
 	helper.KeepETag(cr, etag)
-	err = updateStatus(cr, out)
-	if err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot update status")
-	}
+	updateStatus(cr, out, c.keys)
 
 	return managed.ExternalUpdate{
 		ConnectionDetails: managed.ConnectionDetails{},
@@ -315,36 +413,72 @@ func (c *external) Disconnect(context.Context) error {
 	return nil
 }
 
-func updateStatus(pc *v1alpha1.PipelineConfig, got *gocd.PipelineConfig) error {
-	b, err := json.Marshal(got)
-	if err != nil {
-		return errors.Wrap(err, "error marshalling pipeline config parameters")
+// updateStatus records the observed pipeline in status. Only names and
+// fingerprints keyed by keys are copied, never values that could hold
+// credentials.
+func updateStatus(pc *v1alpha1.PipelineConfig, got *gocd.PipelineConfig, keys *keyring.Keyring) {
+	if got == nil {
+		return
+	}
+	obs := v1alpha1.PipelineConfigObservation{
+		Name:          ptr.Deref(got.Name),
+		Group:         ptr.Deref(got.Group),
+		LabelTemplate: ptr.Deref(got.LabelTemplate),
+		Template:      ptr.Deref(got.Template),
+		Origin:        mapDTOOriginToAPI(got.Origin),
+	}
+	for _, m := range got.Materials {
+		obs.Materials = append(obs.Materials, v1alpha1.MaterialObservation{
+			Type:        v1alpha1.MaterialType(m.Type),
+			Name:        materialName(m),
+			Fingerprint: materialFingerprint(keys, m),
+		})
+	}
+	for _, s := range got.Stages {
+		stage := v1alpha1.StageObservation{Name: s.Name}
+		for _, j := range s.Jobs {
+			stage.Jobs = append(stage.Jobs, j.Name)
+		}
+		obs.Stages = append(obs.Stages, stage)
 	}
-	pc.Status.AtProvider = &runtime.RawExtension{Raw: b}
-	return nil
-}
-
-func isUpToDate(ctx context.Context, kube client.Client, pc *v1alpha1.PipelineConfig, got *gocd.PipelineConfig) (bool, error) {
-	specHashes, err := calculateHashes(ctx, kube, pc.Spec.ForProvider)
-	if err != nil {
-		if k8serrors.IsNotFound(errors.Cause(err)) {
-			return false, nil
+	if l := got.Links; l != nil {
+		if l.Self != nil {
+			obs.Links.Self.Href = l.Self.Href
+		}
+		if l.Doc != nil {
+			obs.Links.Doc.Href = l.Doc.Href
+		}
+		if l.Find != nil {
+			obs.Links.Find.Href = l.Find.Href
 		}
-		return false, errors.Wrap(err, "cannot calculate hashes from spec")
 	}
+	pc.Status.AtProvider = obs
+}
 
-	environmentIsEqual := len(specHashes) > 0 && len(pc.Status.EnvironmentVariableHashes) > 0 &&
-		len(specHashes) == len(pc.Status.EnvironmentVariableHashes) &&
-		reflect.DeepEqual(specHashes, pc.Status.EnvironmentVariableHashes)
-
-	if !environmentIsEqual {
-		return false, nil
-	}
+func materialName(m gocd.PipelineConfigMaterial) string {
+	switch a := m.Attributes.(type) {
+	case *gocd.PipelineConfigMaterialAttributesGit:
+		return ptr.Deref(a.Name)
+	case *gocd.PipelineConfigMaterialAttributesSvn:
+		return ptr.Deref(a.Name)
+	case *gocd.PipelineConfigMaterialAttributesHg:
+		return ptr.Deref(a.Name)
+	case *gocd.PipelineConfigMaterialAttributesP4:
+		return ptr.Deref(a.Name)
+	case *gocd.PipelineConfigMaterialAttributesTfs:
+		return ptr.Deref(a.Name)
+	case *gocd.PipelineConfigMaterialAttributesDependency:
+		return ptr.Deref(a.Name)
+	}
+	return ""
+}
 
-	desired, err := mapAPIToDtoPipelineConfig(ctx, kube, pc.Spec.ForProvider)
-	if err != nil {
-		return false, errors.Wrap(err, "cannot map api to dto")
+// materialFingerprint hashes the source of a material with the keyring, so
+// that credentials in e.g. a git URL neither end up in status nor can be
+// guessed from it.
+func materialFingerprint(keys *keyring.Keyring, m gocd.PipelineConfigMaterial) string {
+	if m.Attributes == nil {
+		return ""
 	}
-
-	return desired.Equal(got), nil
+	return keys.Hash(m.GetID())
 }
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelineconfig
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/keyring"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+// AnnotationKeySecureVariables records, for every secure variable, the keyed
+// hash of its value and the encrypted value GoCD stores for it. GoCD never
+// returns the value of a secure variable and encrypts with a random
+// initialization vector, so a value can only be compared with GoCD through the
+// encrypted value it was applied as. The record is kept in an annotation
+// rather than in status so it survives a status wipe, e.g. a backup and
+// restore. Deleting the annotation is not reported as drift: the values are
+// encrypted again and GoCD is updated with them, even if they did not change.
+// Imported pipelines are recorded without hashes, see RecordEncryptedValues.
+const AnnotationKeySecureVariables = "gocd.crossplane.io/secure-variables"
+
+// A secureValue binds the value of a secure variable to the encrypted value
+// GoCD stores for it.
+type secureValue struct {
+	Hash           string `json:"hash"`
+	EncryptedValue string `json:"encryptedValue"`
+}
+
+// getSecureValues returns the secure values recorded on a PipelineConfig. A
+// malformed record is treated as no record, so the values are encrypted again.
+func getSecureValues(pc *v1alpha1.PipelineConfig) map[string]secureValue {
+	out := map[string]secureValue{}
+	if a, ok := pc.GetAnnotations()[AnnotationKeySecureVariables]; ok {
+		if err := json.Unmarshal([]byte(a), &out); err != nil {
+			return map[string]secureValue{}
+		}
+	}
+	return out
+}
+
+func setSecureValues(pc *v1alpha1.PipelineConfig, values map[string]secureValue) error {
+	if len(values) == 0 {
+		meta.RemoveAnnotations(pc, AnnotationKeySecureVariables)
+		return nil
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode secure variables")
+	}
+	meta.AddAnnotations(pc, map[string]string{AnnotationKeySecureVariables: string(b)})
+	return nil
+}
+
+// RecordEncryptedValues records the encrypted values GoCD stores for the
+// secure variables of an observed pipeline on the PipelineConfig adopting it.
+// Their values are unknown, so no hash is recorded: the first reconcile binds
+// each record to the value its variable resolves to, trusting it to be the
+// value GoCD encrypted, so that adopting a pipeline does not apply its secure
+// variables again.
+func RecordEncryptedValues(pc *v1alpha1.PipelineConfig, got *gocd.PipelineConfig) error {
+	values := map[string]secureValue{}
+	_ = forEachVariable(got, func(key string, v *gocd.EnvironmentVariable) error {
+		if v.Secure && v.EncryptedValue != "" {
+			values[key] = secureValue{EncryptedValue: v.EncryptedValue}
+		}
+		return nil
+	})
+	return setSecureValues(pc, values)
+}
+
+// adopted returns whether a secure value was recorded by
+// RecordEncryptedValues and is not bound to a value yet.
+func (sv secureValue) adopted() bool {
+	return sv.Hash == "" && sv.EncryptedValue != ""
+}
+
+// migrateStatusHashes records the hashes earlier versions of the provider kept
+// in status.environmentVariableHashes in the secure values annotation. GoCD
+// does not reveal the value a hash was computed from, so each secure variable's
+// hash is bound to the encrypted value GoCD stores for it. The value is then
+// only encrypted again if the hash does not match the resolved value. It
+// reports whether the annotation changed.
+func migrateStatusHashes(pc *v1alpha1.PipelineConfig, got *gocd.PipelineConfig) (bool, error) {
+	hashes := pc.Status.EnvironmentVariableHashes
+	if len(hashes) == 0 {
+		return false, nil
+	}
+	pc.Status.EnvironmentVariableHashes = nil
+
+	recorded := getSecureValues(pc)
+	changed := false
+	_ = forEachVariable(got, func(key string, v *gocd.EnvironmentVariable) error {
+		hash, ok := hashes[key]
+		if _, exists := recorded[key]; exists || !ok || !v.Secure || v.EncryptedValue == "" {
+			return nil
+		}
+		recorded[key] = secureValue{Hash: hash, EncryptedValue: v.EncryptedValue}
+		changed = true
+		return nil
+	})
+	if !changed {
+		return false, nil
+	}
+	return true, setSecureValues(pc, recorded)
+}
+
+// encryptSecureVariables replaces the values of the secure variables of a
+// pipeline with resolved values by encrypted values. The encrypted value
+// recorded for a variable is reused while its value is unchanged; otherwise
+// GoCD encrypts the value. A recorded hash computed with a previous key, or
+// without a key as those migrated from status, is replaced without encrypting
+// the value again, as is the missing hash of a value recorded on import. It
+// returns the secure values of the pipeline.
+func encryptSecureVariables(ctx context.Context, enc gocd.EncryptionService, keys *keyring.Keyring, desired *gocd.PipelineConfig, recorded map[string]secureValue) (map[string]secureValue, error) {
+	out := map[string]secureValue{}
+	err := forEachVariable(desired, func(key string, v *gocd.EnvironmentVariable) error {
+		if !v.Secure {
+			return nil
+		}
+		sv, ok := recorded[key]
+		switch {
+		case ok && sv.adopted():
+			sv.Hash = keys.Hash(v.Value)
+		case !ok || !keys.Verify(v.Value, sv.Hash):
+			encrypted, err := enc.Encrypt(ctx, v.Value)
+			if err != nil {
+				return errors.Wrapf(err, "cannot encrypt variable %s", key)
+			}
+			sv = secureValue{Hash: keys.Hash(v.Value), EncryptedValue: encrypted}
+		case !keys.Current(sv.Hash):
+			sv.Hash = keys.Hash(v.Value)
+		}
+		out[key] = sv
+		v.Value = ""
+		v.EncryptedValue = sv.EncryptedValue
+		return nil
+	})
+	return out, err
+}
+
+// sealSecureVariables replaces the values of the secure variables of a
+// pipeline with the encrypted values recorded for them, without asking GoCD
+// to encrypt anything, as a dry run must not. A value that does not match its
+// recorded hash gets Redacted as its encrypted value, which differs from any
+// encrypted value GoCD stores, so that it is reported as changed.
+func sealSecureVariables(keys *keyring.Keyring, desired *gocd.PipelineConfig, recorded map[string]secureValue) {
+	_ = forEachVariable(desired, func(key string, v *gocd.EnvironmentVariable) error {
+		if !v.Secure {
+			return nil
+		}
+		v.EncryptedValue = gocd.Redacted
+		if sv, ok := recorded[key]; ok && (sv.adopted() || keys.Verify(v.Value, sv.Hash)) {
+			v.EncryptedValue = sv.EncryptedValue
+		}
+		v.Value = ""
+		return nil
+	})
+}
+
+// encryptSecureValues encrypts the secure variables of desired and records
+// their encrypted values on the PipelineConfig. It returns whether the record
+// changed.
+func (c *external) encryptSecureValues(ctx context.Context, pc *v1alpha1.PipelineConfig, desired *gocd.PipelineConfig) (bool, error) {
+	recorded := getSecureValues(pc)
+	values, err := encryptSecureVariables(ctx, c.encryption, c.keys, desired, recorded)
+	if err != nil {
+		return false, err
+	}
+	if maps.Equal(values, recorded) {
+		return false, nil
+	}
+	return true, setSecureValues(pc, values)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelineconfig
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+)
+
+const (
+	// secretRefsIndex indexes PipelineConfigs by the Secrets they read values
+	// from, as namespace/name.
+	secretRefsIndex = "spec.forProvider.secretRefs"
+	// configMapRefsIndex indexes PipelineConfigs by the ConfigMaps they read
+	// values from, as namespace/name.
+	configMapRefsIndex = "spec.forProvider.configMapRefs"
+)
+
+// setupIndexes registers the indexes used to find the PipelineConfigs that
+// reference a Secret or ConfigMap.
+func setupIndexes(ctx context.Context, mgr ctrl.Manager) error {
+	fi := mgr.GetFieldIndexer()
+	if err := fi.IndexField(ctx, &v1alpha1.PipelineConfig{}, secretRefsIndex, indexSecretRefs); err != nil {
+		return errors.Wrap(err, "cannot index PipelineConfigs by Secret")
+	}
+	if err := fi.IndexField(ctx, &v1alpha1.PipelineConfig{}, configMapRefsIndex, indexConfigMapRefs); err != nil {
+		return errors.Wrap(err, "cannot index PipelineConfigs by ConfigMap")
+	}
+	return nil
+}
+
+func indexSecretRefs(o client.Object) []string {
+	return references(o, func(from *v1alpha1.ValueSource) *types.NamespacedName {
+		if from.SecretKeyRef == nil {
+			return nil
+		}
+		return &types.NamespacedName{Namespace: from.SecretKeyRef.Namespace, Name: from.SecretKeyRef.Name}
+	}, func(from v1alpha1.EnvFromSource) *types.NamespacedName {
+		if from.SecretRef == nil {
+			return nil
+		}
+		return &types.NamespacedName{Namespace: from.SecretRef.Namespace, Name: from.SecretRef.Name}
+	})
+}
+
+func indexConfigMapRefs(o client.Object) []string {
+	return references(o, func(from *v1alpha1.ValueSource) *types.NamespacedName {
+		if from.ConfigMapKeyRef == nil {
+			return nil
+		}
+		return &types.NamespacedName{Namespace: from.ConfigMapKeyRef.Namespace, Name: from.ConfigMapKeyRef.Name}
+	}, func(from v1alpha1.EnvFromSource) *types.NamespacedName {
+		if from.ConfigMapRef == nil {
+			return nil
+		}
+		return &types.NamespacedName{Namespace: from.ConfigMapRef.Namespace, Name: from.ConfigMapRef.Name}
+	})
+}
+
+// references returns the distinct objects the value and envFrom sources of the
+// parameters and environment variables of a PipelineConfig refer to, as
+// selected by ref and refFrom.
+func references(o client.Object, ref func(from *v1alpha1.ValueSource) *types.NamespacedName, refFrom func(from v1alpha1.EnvFromSource) *types.NamespacedName) []string {
+	pc, ok := o.(*v1alpha1.PipelineConfig)
+	if !ok {
+		return nil
+	}
+
+	var out []string
+	seen := map[string]bool{}
+	add := func(nn *types.NamespacedName) {
+		if nn == nil || seen[nn.String()] {
+			return
+		}
+		seen[nn.String()] = true
+		out = append(out, nn.String())
+	}
+	addAll := func(envFrom []v1alpha1.EnvFromSource, vars []v1alpha1.EnvironmentVariable) {
+		for _, from := range envFrom {
+			add(refFrom(from))
+		}
+		for _, v := range vars {
+			if v.ValueFrom != nil {
+				add(ref(v.ValueFrom))
+			}
+		}
+	}
+
+	sp := pc.Spec.ForProvider
+	for _, p := range sp.Parameters {
+		if p.ValueFrom != nil {
+			add(ref(p.ValueFrom))
+		}
+	}
+	addAll(sp.EnvFrom, sp.EnvironmentVariables)
+	for _, s := range sp.Stages {
+		addAll(s.EnvFrom, s.EnvironmentVariables)
+		for _, j := range s.Jobs {
+			addAll(j.EnvFrom, j.EnvironmentVariables)
+		}
+	}
+	return out
+}
+
+// enqueueReferencing returns a handler that enqueues the PipelineConfigs whose
+// values are read from the Secret or ConfigMap that changed.
+func enqueueReferencing(kube client.Reader, index string, log logging.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := &v1alpha1.PipelineConfigList{}
+		key := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}.String()
+		if err := kube.List(ctx, l, client.MatchingFields{index: key}); err != nil {
+			log.Info("Cannot list PipelineConfigs referencing changed object", "object", key, "error", err)
+			return nil
+		}
+		out := make([]reconcile.Request, 0, len(l.Items))
+		for _, pc := range l.Items {
+			out = append(out, reconcile.Request{NamespacedName: types.NamespacedName{Name: pc.GetName()}})
+		}
+		return out
+	})
+}
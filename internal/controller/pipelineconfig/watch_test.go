@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipelineconfig
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
+)
+
+func referencingPipeline(name string, secrets ...string) *v1alpha1.PipelineConfig {
+	pc := &v1alpha1.PipelineConfig{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	pc.Spec.ForProvider.Stages = []v1alpha1.Stage{{Name: "build", Jobs: []v1alpha1.Job{{Name: "compile"}}}}
+	for _, s := range secrets {
+		v := v1alpha1.EnvironmentVariable{Name: s, ValueFrom: &v1alpha1.ValueSource{
+			SecretKeyRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: s, Namespace: "ci"}, Key: "token"},
+		}}
+		job := &pc.Spec.ForProvider.Stages[0].Jobs[0]
+		job.EnvironmentVariables = append(job.EnvironmentVariables, v, v)
+	}
+	pc.Spec.ForProvider.EnvironmentVariables = []v1alpha1.EnvironmentVariable{{Name: "REGISTRY", ValueFrom: &v1alpha1.ValueSource{
+		ConfigMapKeyRef: &v1alpha1.ConfigMapKeySelector{Name: "registry", Namespace: "ci", Key: "url"},
+	}}}
+	return pc
+}
+
+func TestIndexRefs(t *testing.T) {
+	pc := referencingPipeline("up42", "deploy-token", "api-token")
+	if diff := cmp.Diff([]string{"ci/deploy-token", "ci/api-token"}, indexSecretRefs(pc)); diff != "" {
+		t.Errorf("indexSecretRefs(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"ci/registry"}, indexConfigMapRefs(pc)); diff != "" {
+		t.Errorf("indexConfigMapRefs(...): -want, +got:\n%s", diff)
+	}
+
+	pc.Spec.ForProvider.Stages[0].EnvFrom = []v1alpha1.EnvFromSource{
+		{SecretRef: &xpv1.SecretReference{Name: "deploy-token", Namespace: "ci"}},
+		{SecretRef: &xpv1.SecretReference{Name: "shared", Namespace: "ci"}},
+		{ConfigMapRef: &v1alpha1.ConfigMapReference{Name: "defaults", Namespace: "ci"}},
+	}
+	if diff := cmp.Diff([]string{"ci/deploy-token", "ci/shared", "ci/api-token"}, indexSecretRefs(pc)); diff != "" {
+		t.Errorf("indexSecretRefs(...): envFrom: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"ci/registry", "ci/defaults"}, indexConfigMapRefs(pc)); diff != "" {
+		t.Errorf("indexConfigMapRefs(...): envFrom: -want, +got:\n%s", diff)
+	}
+
+	pc.Spec.ForProvider.Parameters = []v1alpha1.Parameter{
+		{Name: "DEPLOY_TARGET", Value: ptr.ToPtr("production")},
+		{Name: "REGISTRY", ValueFrom: &v1alpha1.ValueSource{
+			ConfigMapKeyRef: &v1alpha1.ConfigMapKeySelector{Name: "platform", Namespace: "ci", Key: "registry"},
+		}},
+	}
+	if diff := cmp.Diff([]string{"ci/platform", "ci/registry", "ci/defaults"}, indexConfigMapRefs(pc)); diff != "" {
+		t.Errorf("indexConfigMapRefs(...): parameters: -want, +got:\n%s", diff)
+	}
+	if got := indexSecretRefs(&corev1.Secret{}); got != nil {
+		t.Errorf("indexSecretRefs(...): want no references for other kinds, got %v", got)
+	}
+}
+
+func TestEnqueueReferencing(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	kube := fake.NewClientBuilder().
+		WithScheme(s).
+		WithIndex(&v1alpha1.PipelineConfig{}, secretRefsIndex, indexSecretRefs).
+		WithObjects(referencingPipeline("up42", "deploy-token"), referencingPipeline("down42", "api-token")).
+		Build()
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	h := enqueueReferencing(kube, secretRefsIndex, logging.NewNopLogger())
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deploy-token", Namespace: "ci"}}
+	h.Update(context.Background(), event.UpdateEvent{ObjectOld: secret, ObjectNew: secret}, q)
+
+	if q.Len() != 1 {
+		t.Fatalf("Update(...): want one PipelineConfig enqueued, got %d", q.Len())
+	}
+	got, _ := q.Get()
+	if want := (reconcile.Request{NamespacedName: types.NamespacedName{Name: "up42"}}); got != want {
+		t.Errorf("Update(...): want %v enqueued, got %v", want, got)
+	}
+}
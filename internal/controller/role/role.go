@@ -18,7 +18,6 @@ package role
 
 import (
 	"context"
-	"encoding/json"
 	"maps"
 	"slices"
 	"strings"
@@ -35,8 +34,14 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/statemetrics"
 	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
 	apisv1alpha1 "github.com/marquesgui/provider-gocd/apis/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/clients"
 	"github.com/marquesgui/provider-gocd/internal/controller/helper"
+	"github.com/marquesgui/provider-gocd/internal/drift"
+	"github.com/marquesgui/provider-gocd/internal/dryrun"
 	"github.com/marquesgui/provider-gocd/internal/features"
+	"github.com/marquesgui/provider-gocd/internal/ignore"
+	"github.com/marquesgui/provider-gocd/internal/lateinit"
+	"github.com/marquesgui/provider-gocd/internal/tracing"
 	"github.com/marquesgui/provider-gocd/pkg/gocd"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -59,28 +64,9 @@ type gocdRoleService interface {
 	Delete(ctx context.Context, id string, etag string) error
 }
 
-// newServiceFn builds the real GoCD Role service from credentials bytes.
-var newServiceFn = func(creds []byte) (any, error) {
-	type c struct {
-		BaseURL  string `json:"baseURL"`
-		Username string `json:"username"`
-		Password string `json:"password"`
-		Token    string `json:"token"`
-		Insecure bool   `json:"insecure"`
-	}
-	var cfg c
-	_ = json.Unmarshal(creds, &cfg)
-	gc, err := gocd.New(gocd.Config{
-		BaseURL:  cfg.BaseURL,
-		Username: cfg.Username,
-		Password: cfg.Password,
-		Token:    cfg.Token,
-		Insecure: cfg.Insecure,
-	})
-	if err != nil {
-		return nil, err
-	}
-	return gc.Roles(), nil
+// newServiceFn returns the GoCD service this controller manages.
+var newServiceFn = func(gc gocd.Client) any {
+	return gc.Roles()
 }
 
 // Setup adds a controller that reconciles role managed resources.
@@ -92,15 +78,20 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), apisv1alpha1.StoreConfigGroupVersionKind))
 	}
 
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	dryRun := o.Features.Enabled(features.EnableDryRun)
 	opts := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(tracing.NewConnector(v1alpha1.RoleKind, dryrun.NewConnector(&connector{
 			kube:         mgr.GetClient(),
 			usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			clients:      clients.DefaultCache,
 			newServiceFn: newServiceFn,
-		}),
+			drift:        drift.NewReporter(recorder),
+			dryRun:       dryRun,
+		}, dryRun, recorder))),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		managed.WithRecorder(recorder),
 		managed.WithConnectionPublishers(cps...),
 		managed.WithManagementPolicies(),
 	}
@@ -137,7 +128,10 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 type connector struct {
 	kube         client.Client
 	usage        resource.Tracker
-	newServiceFn func(creds []byte) (any, error)
+	clients      *clients.Cache
+	newServiceFn func(gc gocd.Client) any
+	drift        *drift.Reporter
+	dryRun       bool
 }
 
 // Connect typically produces an ExternalClient by:
@@ -160,29 +154,41 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errGetPC)
 	}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+	if err := clients.CheckHealth(pc); err != nil {
+		return nil, err
+	}
+
+	creds, err := clients.ExtractCredentials(ctx, c.kube, pc)
 	if err != nil {
 		return nil, errors.Wrap(err, errGetCreds)
 	}
 
-	svc, err := c.newServiceFn(data)
+	gc, err := c.clients.Get(pc, creds)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
+	svc := c.newServiceFn(gc)
+
 	rs, ok := svc.(gocdRoleService)
 	if !ok {
 		return nil, errors.New("returned service does not implement gocdRoleService")
 	}
 
-	return &external{service: rs}, nil
+	return &external{service: rs, drift: c.drift, dryRun: dryrun.Enabled(mg, c.dryRun)}, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
 	service gocdRoleService
+	drift   *drift.Reporter
+	// Whether the managed resource is in dry run, in which case Observe does
+	// not change it.
+	dryRun bool
+	// The role as observed during this reconcile, which ignored fields are
+	// filled from when it is updated.
+	observed *gocd.Role
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -203,11 +209,31 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if got == nil {
 		return managed.ExternalObservation{ResourceExists: false}, nil
 	}
+	c.observed = got
 
 	updateStatus(r, got)
 	helper.KeepETag(r, etag)
 
-	upToDate := isUpToDate(r, got)
+	// A dry run late initializes a copy, which the desired role is built
+	// from but which is never persisted.
+	target := r
+	if c.dryRun {
+		target = r.DeepCopy()
+	}
+	lateInitialized := lateInitialize(&target.Spec.ForProvider, got)
+	desired := createRoleRequest(name, target)
+	if err := ignore.Apply(&desired, got, r.Spec.IgnoreChanges); err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, "cannot ignore changes")
+	}
+	upToDate := isUpToDate(desired, got)
+
+	var diffs []drift.Difference
+	if !upToDate {
+		if diffs, err = drift.Diff(desired, got); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, "cannot compare role")
+		}
+	}
+	c.drift.Report(r, &r.Status.Drift, diffs)
 	if upToDate {
 		r.SetConditions(xpv1.Available())
 	} else {
@@ -215,9 +241,10 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: managed.ConnectionDetails{},
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: lateInitialized && !c.dryRun,
+		ConnectionDetails:       managed.ConnectionDetails{},
 	}, nil
 }
 
@@ -253,6 +280,9 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	name := meta.GetExternalName(cr)
 	in := createRoleRequest(name, cr)
+	if err := ignore.Apply(&in, c.observed, cr.Spec.IgnoreChanges); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, "cannot ignore changes")
+	}
 
 	etag := helper.GetETag(cr)
 	out, newETag, err := c.service.Update(ctx, name, in, etag)
@@ -286,17 +316,25 @@ func (c *external) Disconnect(_ context.Context) error {
 	return nil
 }
 
-func isUpToDate(cr *v1alpha1.Role, got *gocd.Role) bool { //nolint:gocyclo
-	current := &cr.Spec.ForProvider
-	if meta.GetExternalName(cr) != got.Name || current.Type != got.Type {
+// isUpToDate reports whether the observed role is configured as desired.
+// Users, properties and policies compare as sets.
+func isUpToDate(desired gocd.Role, got *gocd.Role) bool {
+	if desired.Name != got.Name || desired.Type != got.Type {
 		return false
 	}
 
-	if current.Attributes.AuthConfigID != got.Attributes.AuthConfigID {
+	attributes := func(a *gocd.RoleAttributes) gocd.RoleAttributes {
+		if a == nil {
+			return gocd.RoleAttributes{}
+		}
+		return *a
+	}
+	current, observed := attributes(desired.Attributes), attributes(got.Attributes)
+	if current.AuthConfigID != observed.AuthConfigID {
 		return false
 	}
 
-	propertiesIsUpToDate := func(current []v1alpha1.KeyValue, got []gocd.ConfigProperty) bool {
+	propertiesIsUpToDate := func(current []gocd.ConfigProperty, got []gocd.ConfigProperty) bool {
 		if len(current) != len(got) {
 			return false
 		}
@@ -309,7 +347,7 @@ func isUpToDate(cr *v1alpha1.Role, got *gocd.Role) bool { //nolint:gocyclo
 			mapGot[v.Key] = v.Value
 		}
 		return maps.Equal(mapCurrent, mapGot)
-	}(current.Attributes.Properties, got.Attributes.Properties)
+	}(current.Properties, observed.Properties)
 
 	userIsUpToDate := func(current []string, got []string) bool {
 		if len(current) != len(got) {
@@ -320,49 +358,14 @@ func isUpToDate(cr *v1alpha1.Role, got *gocd.Role) bool { //nolint:gocyclo
 		slices.Sort(ac)
 		slices.Sort(bc)
 		return slices.Equal(ac, bc)
-	}(current.Attributes.Users, got.Attributes.Users)
+	}(current.Users, observed.Users)
 
-	policiesIsUpToDate := func(c []v1alpha1.RoleParametersPolicy, g []gocd.Policy) bool {
+	policiesIsUpToDate := func(c []gocd.Policy, g []gocd.Policy) bool {
 		if len(c) != len(g) {
 			return false
 		}
 
-		type sortablePolicy struct {
-			Permission string
-			Action     string
-			Type       string
-			Resource   string
-		}
-
-		toPolicies := func(in any) []sortablePolicy {
-			var result []sortablePolicy
-			switch v := in.(type) {
-			case []v1alpha1.RoleParametersPolicy:
-				for _, p := range v {
-					result = append(result, sortablePolicy{
-						Permission: p.Permission,
-						Action:     p.Action,
-						Type:       p.Type,
-						Resource:   p.Resource,
-					})
-				}
-			case []gocd.Policy:
-				for _, p := range v {
-					result = append(result, sortablePolicy{
-						Permission: p.Permission,
-						Action:     p.Action,
-						Type:       p.Type,
-						Resource:   p.Resource,
-					})
-				}
-			}
-			return result
-		}
-
-		cp := toPolicies(c)
-		gp := toPolicies(g)
-
-		sortFunc := func(a, b sortablePolicy) int {
+		sortFunc := func(a, b gocd.Policy) int {
 			if a.Permission != b.Permission {
 				return strings.Compare(a.Permission, b.Permission)
 			}
@@ -374,15 +377,28 @@ func isUpToDate(cr *v1alpha1.Role, got *gocd.Role) bool { //nolint:gocyclo
 			}
 			return strings.Compare(a.Resource, b.Resource)
 		}
+		cp := slices.Clone(c)
+		gp := slices.Clone(g)
 		slices.SortFunc(cp, sortFunc)
 		slices.SortFunc(gp, sortFunc)
 
 		return slices.Equal(cp, gp)
-	}(current.Policy, got.Policy)
+	}(desired.Policy, got.Policy)
 
 	return propertiesIsUpToDate && userIsUpToDate && policiesIsUpToDate
 }
 
+// lateInitialize fills the auth config of a plugin role from the observed
+// role. Users, properties and policy are not defaulted by GoCD, so they are
+// never late initialized and removing them from spec removes them from GoCD.
+// It reports whether spec was changed.
+func lateInitialize(spec *v1alpha1.RoleParameters, got *gocd.Role) bool {
+	if got.Attributes == nil {
+		return false
+	}
+	return lateinit.Value(&spec.Attributes.AuthConfigID, got.Attributes.AuthConfigID)
+}
+
 func updateStatus(cr *v1alpha1.Role, got *gocd.Role) {
 	cr.Status.AtProvider.Name = got.Name
 	cr.Status.AtProvider.Type = got.Type
@@ -428,7 +444,7 @@ func updateStatus(cr *v1alpha1.Role, got *gocd.Role) {
 func createRoleRequest(name string, cr *v1alpha1.Role) gocd.Role {
 	prop := make([]gocd.ConfigProperty, 0)
 	for _, v := range cr.Spec.ForProvider.Attributes.Properties {
-		prop = append(prop, gocd.ConfigProperty{Key: v.Key, Value: v.Value})
+		prop = append(prop, gocd.ConfigProperty{Key: v.Key, Value: v.Value, EncryptedValue: v.EncryptedValue})
 	}
 
 	poly := make([]gocd.Policy, 0)
@@ -0,0 +1,188 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package role
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/gocd/gocdtest"
+)
+
+// TestLifecycle reconciles a role end to end against the fake GoCD server.
+func TestLifecycle(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	gc, err := gocd.New(gocd.Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &external{service: gc.Roles()}
+	ctx := context.Background()
+
+	cr := &v1alpha1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "devs"},
+		Spec: v1alpha1.RoleSpec{ForProvider: v1alpha1.RoleParameters{
+			Name:       "devs",
+			Type:       "gocd",
+			Attributes: v1alpha1.RoleParametersAttributes{Users: []string{"alice"}},
+		}},
+	}
+	meta.SetExternalName(cr, "devs")
+
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || obs.ResourceExists {
+		t.Fatalf("Observe(...) before create: exists=%v, err=%v", obs.ResourceExists, err)
+	}
+
+	if _, err := e.Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"devs"}, srv.IDs(gocdtest.Roles)); diff != "" {
+		t.Errorf("Create(...): -want, +got roles:\n%s", diff)
+	}
+
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceExists || !obs.ResourceUpToDate {
+		t.Fatalf("Observe(...) after create: exists=%v, upToDate=%v, err=%v", obs.ResourceExists, obs.ResourceUpToDate, err)
+	}
+
+	cr.Spec.ForProvider.Attributes.Users = []string{"alice", "bob"}
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || obs.ResourceUpToDate {
+		t.Fatalf("Observe(...) after spec change: upToDate=%v, err=%v", obs.ResourceUpToDate, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceUpToDate {
+		t.Fatalf("Observe(...) after update: upToDate=%v, err=%v", obs.ResourceUpToDate, err)
+	}
+
+	if _, err := e.Delete(ctx, cr); err != nil {
+		t.Fatalf("Delete(...): unexpected error: %v", err)
+	}
+	obs, err = e.Observe(ctx, cr)
+	if err != nil || obs.ResourceExists {
+		t.Fatalf("Observe(...) after delete: exists=%v, err=%v", obs.ResourceExists, err)
+	}
+}
+
+func TestIgnoreChanges(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	gc, err := gocd.New(gocd.Config{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	cr := &v1alpha1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "devs"},
+		Spec: v1alpha1.RoleSpec{
+			ForProvider: v1alpha1.RoleParameters{
+				Name:       "devs",
+				Type:       "gocd",
+				Attributes: v1alpha1.RoleParametersAttributes{Users: []string{"alice"}},
+			},
+			IgnoreChanges: []string{"/attributes/users"},
+		},
+	}
+	meta.SetExternalName(cr, "devs")
+	if _, err := (&external{service: gc.Roles()}).Create(ctx, cr); err != nil {
+		t.Fatalf("Create(...): unexpected error: %v", err)
+	}
+
+	// Someone else manages the users of the role.
+	stored, _ := srv.Get(gocdtest.Roles, "devs")
+	stored["attributes"] = map[string]any{"users": []any{"carol"}}
+	if err := srv.Seed(gocdtest.Roles, stored); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &external{service: gc.Roles()}
+	obs, err := e.Observe(ctx, cr)
+	if err != nil || !obs.ResourceUpToDate {
+		t.Fatalf("Observe(...) with ignored users changed: upToDate=%v, err=%v", obs.ResourceUpToDate, err)
+	}
+
+	cr.Spec.ForProvider.Policy = []v1alpha1.RoleParametersPolicy{{Permission: "allow", Action: "view", Type: "environment", Resource: "*"}}
+	e = &external{service: gc.Roles()}
+	if obs, err := e.Observe(ctx, cr); err != nil || obs.ResourceUpToDate {
+		t.Fatalf("Observe(...) after spec change: upToDate=%v, err=%v", obs.ResourceUpToDate, err)
+	}
+	if _, err := e.Update(ctx, cr); err != nil {
+		t.Fatalf("Update(...): unexpected error: %v", err)
+	}
+	stored, _ = srv.Get(gocdtest.Roles, "devs")
+	if diff := cmp.Diff([]any{"carol"}, stored["attributes"].(map[string]any)["users"]); diff != "" {
+		t.Errorf("Update(...): want ignored users kept, -want, +got:\n%s", diff)
+	}
+	if policy, _ := stored["policy"].([]any); len(policy) != 1 {
+		t.Errorf("Update(...): want policy applied, got %v", stored["policy"])
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	got := &gocd.Role{
+		Name: "admins",
+		Type: "plugin",
+		Attributes: &gocd.RoleAttributes{
+			AuthConfigID: "ldap",
+			Users:        []string{"alice"},
+			Properties:   []gocd.ConfigProperty{{Key: "UserGroupMembershipAttribute", Value: "memberOf"}},
+		},
+		Policy: []gocd.Policy{{Permission: "allow", Action: "view", Type: "*", Resource: "*"}},
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   v1alpha1.RoleParameters
+		want   v1alpha1.RoleParameters
+		wantLI bool
+	}{
+		"AuthConfigID": {
+			reason: "The auth config of a plugin role should be filled from the observed role.",
+			spec:   v1alpha1.RoleParameters{Type: "plugin"},
+			want:   v1alpha1.RoleParameters{Type: "plugin", Attributes: v1alpha1.RoleParametersAttributes{AuthConfigID: "ldap"}},
+			wantLI: true,
+		},
+		"ClearedFields": {
+			reason: "Users, properties and policy removed from spec should stay removed.",
+			spec:   v1alpha1.RoleParameters{Type: "plugin", Attributes: v1alpha1.RoleParametersAttributes{AuthConfigID: "ldap"}},
+			want:   v1alpha1.RoleParameters{Type: "plugin", Attributes: v1alpha1.RoleParametersAttributes{AuthConfigID: "ldap"}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			spec := tc.spec
+			if li := lateInitialize(&spec, got); li != tc.wantLI {
+				t.Errorf("\n%s\nlateInitialize(...): want %t, got %t", tc.reason, tc.wantLI, li)
+			}
+			if diff := cmp.Diff(tc.want, spec); diff != "" {
+				t.Errorf("\n%s\nlateInitialize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
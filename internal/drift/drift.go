@@ -0,0 +1,459 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drift reports how an external resource observed in GoCD differs
+// from the state desired by its managed resource.
+package drift
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+// maxValueLength is the length JSON encoded values are truncated to.
+const maxValueLength = 80
+
+// A Difference is a field whose desired and observed values differ.
+type Difference struct {
+	// Path of the field, e.g. stages[build].jobs[compile].timeout.
+	Path string
+	// Desired is the JSON encoded desired value, empty if it is not set.
+	Desired string
+	// Observed is the JSON encoded observed value, empty if it is not set.
+	Observed string
+}
+
+// String returns a one line description of the difference.
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Path, orUnset(d.Desired), orUnset(d.Observed))
+}
+
+func orUnset(v string) string {
+	if v == "" {
+		return "<unset>"
+	}
+	return v
+}
+
+// keys are the fields identifying the elements of an array of objects, in
+// order of preference. Arrays whose elements lack them are compared by index.
+var keys = []string{"name", "key", "id"}
+
+// keyFuncs identify the elements of arrays whose elements have no key field,
+// by the name of the array.
+var keyFuncs = map[string]func(any) (string, bool){
+	"materials": materialID,
+}
+
+// ordered are the arrays whose order matters to GoCD, by name, as compared by
+// gocd.PipelineConfig.Equal. Other arrays are compared as sets.
+var ordered = map[string]bool{"stages": true, "tasks": true, "run_if": true}
+
+// ignored are fields set by GoCD that never appear in a desired state.
+var ignored = map[string]bool{"_links": true}
+
+// Diff compares the JSON representations of a desired and an observed GoCD
+// object. Fields set on either side are compared, with unset and empty values
+// alike since GoCD omits them; elements added to an array in GoCD are
+// reported, and so are stages, tasks and run_if conditions GoCD has in another
+// order. Credentials are redacted. GoCD only reveals them encrypted, so they
+// are compared only when set on both sides, e.g. the encrypted values of
+// secure variables. The values at the redacted paths, e.g.
+// parameters[TOKEN].value, are compared but never revealed either.
+func Diff(desired, observed any, redacted ...string) ([]Difference, error) {
+	d, err := decode(desired)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode desired state")
+	}
+	o, err := decode(observed)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot encode observed state")
+	}
+	df := &differ{redacted: map[string]bool{}}
+	for _, p := range redacted {
+		df.redacted[p] = true
+	}
+	df.diff("", d, o)
+	return df.out, nil
+}
+
+// A differ collects the differences between a desired and an observed state.
+type differ struct {
+	redacted map[string]bool
+	out      []Difference
+}
+
+func decode(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
+
+func (df *differ) diff(path string, desired, observed any) {
+	// GoCD omits empty values.
+	if isEmpty(desired) && isEmpty(observed) {
+		return
+	}
+	switch d := desired.(type) {
+	case map[string]any:
+		o, ok := observed.(map[string]any)
+		if !ok && observed != nil {
+			break
+		}
+		credentials := credentialFields(d, o)
+		for _, k := range sortedKeys(d, o) {
+			switch {
+			case ignored[k]:
+			case credentials[k]:
+				df.diffCredential(join(path, k), d[k], o[k])
+			default:
+				df.diff(join(path, k), d[k], o[k])
+			}
+		}
+		return
+	case []any:
+		o, ok := observed.([]any)
+		if !ok && observed != nil {
+			break
+		}
+		df.diffArrays(path, d, o)
+		return
+	}
+	if !reflect.DeepEqual(desired, observed) {
+		df.out = append(df.out, Difference{Path: path, Desired: df.encode(path, desired), Observed: df.encode(path, observed)})
+	}
+}
+
+// diffCredential reports a credential that differs as redacted on both
+// sides. A credential set on one side only, e.g. a password GoCD returns
+// encrypted, cannot be compared.
+func (df *differ) diffCredential(path string, desired, observed any) {
+	if isEmpty(desired) || isEmpty(observed) || reflect.DeepEqual(desired, observed) {
+		return
+	}
+	df.out = append(df.out, Difference{Path: path, Desired: encode(gocd.Redacted), Observed: encode(gocd.Redacted)})
+}
+
+// credentialFields returns the fields holding credentials in either object.
+func credentialFields(desired, observed map[string]any) map[string]bool {
+	out := map[string]bool{}
+	for _, m := range []map[string]any{desired, observed} {
+		for _, k := range gocd.CredentialFields(m) {
+			out[k] = true
+		}
+	}
+	return out
+}
+
+func (df *differ) diffArrays(path string, desired, observed []any) {
+	if len(desired) == 0 && len(observed) == 0 {
+		return
+	}
+	name := field(path)
+	key, ok := arrayKey(name, desired, observed)
+	if !ok {
+		if !isObjects(desired) || !isObjects(observed) {
+			// Arrays of values, e.g. users or resources, are compared as sets
+			// unless their order matters.
+			equal := equalSets(desired, observed)
+			if ordered[name] {
+				equal = reflect.DeepEqual(desired, observed)
+			}
+			if !equal {
+				df.out = append(df.out, Difference{Path: path, Desired: df.encode(path, desired), Observed: df.encode(path, observed)})
+			}
+			return
+		}
+		for i := 0; i < len(desired) || i < len(observed); i++ {
+			elem := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(observed):
+				df.out = append(df.out, Difference{Path: elem, Desired: df.encode(elem, desired[i])})
+			case i >= len(desired):
+				df.out = append(df.out, Difference{Path: elem, Observed: df.encode(elem, observed[i])})
+			default:
+				df.diff(elem, desired[i], observed[i])
+			}
+		}
+		return
+	}
+
+	byKey := make(map[string]any, len(observed))
+	for _, o := range observed {
+		byKey[key(o)] = o
+	}
+	seen := make(map[string]bool, len(desired))
+	for _, d := range desired {
+		k := key(d)
+		seen[k] = true
+		elem := fmt.Sprintf("%s[%s]", path, k)
+		o, ok := byKey[k]
+		if !ok {
+			df.out = append(df.out, Difference{Path: elem, Desired: df.encode(elem, d)})
+			continue
+		}
+		df.diff(elem, d, o)
+	}
+	for _, o := range observed {
+		if k := key(o); !seen[k] {
+			elem := fmt.Sprintf("%s[%s]", path, k)
+			df.out = append(df.out, Difference{Path: elem, Observed: df.encode(elem, o)})
+		}
+	}
+	if !ordered[name] {
+		return
+	}
+
+	// Elements present on both sides but in another order, e.g. reordered
+	// stages, are reported as a difference of the array.
+	var desiredOrder, observedOrder []any
+	for _, d := range desired {
+		if k := key(d); byKey[k] != nil {
+			desiredOrder = append(desiredOrder, k)
+		}
+	}
+	for _, o := range observed {
+		if k := key(o); seen[k] {
+			observedOrder = append(observedOrder, k)
+		}
+	}
+	if !slices.Equal(desiredOrder, observedOrder) {
+		df.out = append(df.out, Difference{Path: path, Desired: encode(desiredOrder), Observed: encode(observedOrder)})
+	}
+}
+
+// arrayKey returns a function identifying the elements of both arrays, if
+// all of them have a distinct identifier: one computed for the named array,
+// or a field of the elements.
+func arrayKey(name string, desired, observed []any) (func(any) string, bool) {
+	if fn, ok := keyFuncs[name]; ok && distinct(desired, fn) && distinct(observed, fn) {
+		return func(v any) string {
+			id, _ := fn(v)
+			return id
+		}, true
+	}
+	for _, k := range keys {
+		if hasKey(desired, k) && hasKey(observed, k) {
+			return func(v any) string { return v.(map[string]any)[k].(string) }, true
+		}
+	}
+	return nil, false
+}
+
+func distinct(arr []any, fn func(any) (string, bool)) bool {
+	seen := make(map[string]bool, len(arr))
+	for _, e := range arr {
+		id, ok := fn(e)
+		if !ok || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// materialID identifies a material as gocd.PipelineConfig.Equal does, by its
+// type and source, e.g. the URL and branch of a git material.
+func materialID(v any) (string, bool) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	var m gocd.PipelineConfigMaterial
+	if err := json.Unmarshal(b, &m); err != nil || m.Attributes == nil {
+		return "", false
+	}
+	return m.GetID(), true
+}
+
+func hasKey(arr []any, key string) bool {
+	seen := make(map[string]bool, len(arr))
+	for _, e := range arr {
+		m, ok := e.(map[string]any)
+		if !ok {
+			return false
+		}
+		v, ok := m[key].(string)
+		if !ok || seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
+
+func isEmpty(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return t == ""
+	case bool:
+		return !t
+	case float64:
+		return t == 0
+	case []any:
+		return len(t) == 0
+	case map[string]any:
+		return len(t) == 0
+	}
+	return false
+}
+
+func isObjects(arr []any) bool {
+	for _, e := range arr {
+		if _, ok := e.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func equalSets(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[string]int, len(a))
+	for _, v := range a {
+		count[marshal(v)]++
+	}
+	for _, v := range b {
+		k := marshal(v)
+		if count[k] == 0 {
+			return false
+		}
+		count[k]--
+	}
+	return true
+}
+
+// field returns the name of the field at path, e.g. tasks for
+// stages[build].jobs[compile].tasks.
+func field(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys returns the keys of either map, sorted.
+func sortedKeys(a, b map[string]any) []string {
+	out := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		out = append(out, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// encode returns v JSON encoded, with credentials redacted, truncated at
+// maxValueLength.
+func encode(v any) string {
+	if v == nil {
+		return ""
+	}
+	s := marshal(v)
+	if len(s) > maxValueLength {
+		s = s[:maxValueLength-3] + "..."
+	}
+	return s
+}
+
+// encode returns the value at path JSON encoded, with credentials and the
+// redacted paths redacted.
+func (df *differ) encode(path string, v any) string {
+	return encode(df.redact(path, v))
+}
+
+func marshal(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// redact returns a copy of the value at path with credentials and the values
+// at the redacted paths replaced by gocd.Redacted.
+func (df *differ) redact(path string, v any) any {
+	if df.redacted[path] && !isEmpty(v) {
+		return gocd.Redacted
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		credentials := credentialFields(t, nil)
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			if credentials[k] && !isEmpty(e) {
+				out[k] = gocd.Redacted
+				continue
+			}
+			out[k] = df.redact(join(path, k), e)
+		}
+		return out
+	case []any:
+		key, keyed := arrayKey(field(path), t, t)
+		out := make([]any, len(t))
+		for i, e := range t {
+			elem := fmt.Sprintf("%s[%d]", path, i)
+			if keyed {
+				elem = fmt.Sprintf("%s[%s]", path, key(e))
+			}
+			out[i] = df.redact(elem, e)
+		}
+		return out
+	}
+	return v
+}
+
+// Summarize returns a one line description of the differences, naming at most
+// limit of them.
+func Summarize(diffs []Difference, limit int) string {
+	paths := make([]string, 0, limit)
+	for i, d := range diffs {
+		if i == limit {
+			paths = append(paths, fmt.Sprintf("and %d more", len(diffs)-limit))
+			break
+		}
+		paths = append(paths, d.Path)
+	}
+	noun := "fields"
+	if len(diffs) == 1 {
+		noun = "field"
+	}
+	return fmt.Sprintf("observed state differs from spec in %d %s: %s", len(diffs), noun, strings.Join(paths, ", "))
+}
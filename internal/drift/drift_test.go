@@ -0,0 +1,242 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func decodeJSON(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var out map[string]any
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	cases := map[string]struct {
+		desired  string
+		observed string
+		redacted []string
+		want     []Difference
+	}{
+		"Equal": {
+			desired:  `{"name": "up42", "label_template": "${COUNT}"}`,
+			observed: `{"name": "up42", "label_template": "${COUNT}", "_links": {"self": {"href": "x"}}}`,
+		},
+		"EmptyIsUnset": {
+			desired:  `{"name": "up42", "template": null, "group": ""}`,
+			observed: `{"name": "up42", "template": null, "parameters": []}`,
+		},
+		"ObservedOnly": {
+			desired:  `{"name": "up42"}`,
+			observed: `{"name": "up42", "lock_behavior": "none", "timer": {"spec": "0 0 * * * ?"}}`,
+			want: []Difference{
+				{Path: "lock_behavior", Observed: `"none"`},
+				{Path: "timer", Observed: `{"spec":"0 0 * * * ?"}`},
+			},
+		},
+		"Scalar": {
+			desired:  `{"label_template": "${COUNT}"}`,
+			observed: `{"label_template": "${COUNT}-manual"}`,
+			want:     []Difference{{Path: "label_template", Desired: `"${COUNT}"`, Observed: `"${COUNT}-manual"`}},
+		},
+		"KeyedArrays": {
+			desired: `{"stages": [
+				{"name": "build", "jobs": [{"name": "compile", "timeout": 10}]},
+				{"name": "test", "jobs": []}
+			]}`,
+			observed: `{"stages": [
+				{"name": "deploy", "jobs": []},
+				{"name": "build", "jobs": [{"name": "compile", "timeout": 20}]}
+			]}`,
+			want: []Difference{
+				{Path: "stages[build].jobs[compile].timeout", Desired: "10", Observed: "20"},
+				{Path: "stages[test]", Desired: `{"jobs":[],"name":"test"}`},
+				{Path: "stages[deploy]", Observed: `{"jobs":[],"name":"deploy"}`},
+			},
+		},
+		"Reordered": {
+			desired:  `{"stages": [{"name": "build"}, {"name": "test"}, {"name": "deploy"}]}`,
+			observed: `{"stages": [{"name": "test"}, {"name": "build"}, {"name": "audit"}]}`,
+			want: []Difference{
+				{Path: "stages[deploy]", Desired: `{"name":"deploy"}`},
+				{Path: "stages[audit]", Observed: `{"name":"audit"}`},
+				{Path: "stages", Desired: `["build","test"]`, Observed: `["test","build"]`},
+			},
+		},
+		"IndexedArrays": {
+			desired:  `{"tasks": [{"type": "exec", "attributes": {"command": "make"}}]}`,
+			observed: `{"tasks": [{"type": "exec", "attributes": {"command": "make test"}}, {"type": "exec", "attributes": {"command": "true"}}]}`,
+			want: []Difference{
+				{Path: "tasks[0].attributes.command", Desired: `"make"`, Observed: `"make test"`},
+				{Path: "tasks[1]", Observed: `{"attributes":{"command":"true"},"type":"exec"}`},
+			},
+		},
+		"ValuesAreSets": {
+			desired:  `{"users": ["alice", "bob"], "roles": ["admins"]}`,
+			observed: `{"users": ["bob", "alice"], "roles": ["admins", "devs"]}`,
+			want:     []Difference{{Path: "roles", Desired: `["admins"]`, Observed: `["admins","devs"]`}},
+		},
+		"UnorderedKeyedArrays": {
+			desired:  `{"environment_variables": [{"name": "A", "value": "1"}, {"name": "B", "value": "2"}]}`,
+			observed: `{"environment_variables": [{"name": "B", "value": "2"}, {"name": "A", "value": "1"}]}`,
+		},
+		"OrderedValues": {
+			desired:  `{"run_if": ["passed", "failed"]}`,
+			observed: `{"run_if": ["failed", "passed"]}`,
+			want:     []Difference{{Path: "run_if", Desired: `["passed","failed"]`, Observed: `["failed","passed"]`}},
+		},
+		"MaterialsByID": {
+			desired: `{"materials": [
+				{"type": "git", "attributes": {"url": "https://a", "branch": "main", "shallow_clone": true}},
+				{"type": "dependency", "attributes": {"pipeline": "up", "stage": "build"}}
+			]}`,
+			observed: `{"materials": [
+				{"type": "dependency", "attributes": {"pipeline": "up", "stage": "build"}},
+				{"type": "git", "attributes": {"url": "https://a", "branch": "main", "shallow_clone": false}}
+			]}`,
+			want: []Difference{{Path: "materials[git:https://amain].attributes.shallow_clone", Desired: `true`, Observed: `false`}},
+		},
+		"CredentialsAreRedacted": {
+			desired: `{
+				"environment_variables": [{"name": "TOKEN", "value": "s3cr3t", "secure": true}],
+				"properties": [{"key": "Password", "value": "hunter2"}, {"key": "Url", "value": "ldap://a"}],
+				"attributes": {"password": "hunter2"}
+			}`,
+			observed: `{
+				"environment_variables": [{"name": "TOKEN", "encrypted_value": "AES:abc", "secure": true}],
+				"properties": [{"key": "Password", "encrypted_value": "AES:def"}, {"key": "Url", "value": "ldap://b"}],
+				"attributes": {"encrypted_password": "AES:ghi"}
+			}`,
+			want: []Difference{{Path: "properties[Url].value", Desired: `"ldap://a"`, Observed: `"ldap://b"`}},
+		},
+		"EncryptedValuesAreCompared": {
+			desired: `{
+				"environment_variables": [{"name": "TOKEN", "encrypted_value": "AES:abc", "secure": true}, {"name": "KEY", "encrypted_value": "AES:def", "secure": true}],
+				"materials": [{"type": "git", "attributes": {"url": "https://a", "encrypted_password": "AES:ghi"}}]
+			}`,
+			observed: `{
+				"environment_variables": [{"name": "TOKEN", "encrypted_value": "AES:jkl", "secure": true}, {"name": "KEY", "encrypted_value": "AES:def", "secure": true}],
+				"materials": [{"type": "git", "attributes": {"url": "https://a", "encrypted_password": "AES:mno"}}]
+			}`,
+			want: []Difference{
+				{Path: "environment_variables[TOKEN].encrypted_value", Desired: `"REDACTED"`, Observed: `"REDACTED"`},
+				{Path: "materials[git:https://a].attributes.encrypted_password", Desired: `"REDACTED"`, Observed: `"REDACTED"`},
+			},
+		},
+		"RedactedPaths": {
+			desired:  `{"parameters": [{"name": "TOKEN", "value": "s3cr3t"}, {"name": "KEY", "value": "s3cr3t"}, {"name": "ENV", "value": "prod"}]}`,
+			observed: `{"parameters": [{"name": "TOKEN", "value": "hunter2"}, {"name": "ENV", "value": "dev"}]}`,
+			redacted: []string{"parameters[TOKEN].value", "parameters[KEY].value"},
+			want: []Difference{
+				{Path: "parameters[TOKEN].value", Desired: `"REDACTED"`, Observed: `"REDACTED"`},
+				{Path: "parameters[KEY]", Desired: `{"name":"KEY","value":"REDACTED"}`},
+				{Path: "parameters[ENV].value", Desired: `"prod"`, Observed: `"dev"`},
+			},
+		},
+		"AddedElementsAreRedacted": {
+			desired:  `{"environment_variables": []}`,
+			observed: `{"environment_variables": [{"name": "TOKEN", "encrypted_value": "AES:abc", "secure": true}]}`,
+			want: []Difference{
+				{Path: "environment_variables[TOKEN]", Observed: `{"encrypted_value":"REDACTED","name":"TOKEN","secure":true}`},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Diff(decodeJSON(t, tc.desired), decodeJSON(t, tc.observed), tc.redacted...)
+			if err != nil {
+				t.Fatalf("Diff(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Diff(...): -want, +got:\n%s", diff)
+			}
+			for _, d := range got {
+				for _, secret := range []string{"s3cr3t", "hunter2", "AES:"} {
+					if strings.Contains(d.String(), secret) {
+						t.Errorf("Diff(...): %s reveals a credential", d)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDiffPipelineConfig(t *testing.T) {
+	label := "${COUNT}"
+	desired := gocd.PipelineConfig{LabelTemplate: &label}
+	got, err := Diff(desired, &gocd.PipelineConfig{LabelTemplate: &label, Links: &gocd.HALLinks{}})
+	if err != nil || len(got) != 0 {
+		t.Errorf("Diff(...): want no differences between typed objects, got %v, %v", got, err)
+	}
+}
+
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) WithAnnotations(...string) event.Recorder { return r }
+
+func TestReport(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	rec := &recorder{}
+	r := &Reporter{recorder: rec, now: func() time.Time { return now }}
+	mg := &fake.Managed{}
+	diffs := []Difference{{Path: "label_template", Desired: `"a"`, Observed: `"b"`}}
+
+	var status *v1alpha1.Drift
+	r.Report(mg, &status, diffs)
+	if status == nil || status.DetectedAt == nil || !status.DetectedAt.Time.Equal(now) {
+		t.Fatalf("Report(...): want drift detected at %s, got %+v", now, status)
+	}
+	if want := "observed state differs from spec in 1 field: label_template"; status.Summary != want {
+		t.Errorf("Report(...): want summary %q, got %q", want, status.Summary)
+	}
+	if len(rec.events) != 1 || rec.events[0].Reason != ReasonDetected {
+		t.Errorf("Report(...): want one %s event, got %+v", ReasonDetected, rec.events)
+	}
+
+	now = now.Add(time.Minute)
+	r.Report(mg, &status, append(diffs, Difference{Path: "group", Desired: `"x"`}))
+	if len(rec.events) != 1 {
+		t.Errorf("Report(...): want no event for drift that was already reported, got %+v", rec.events)
+	}
+	if len(status.Fields) != 2 || status.DetectedAt.Time.Equal(now) {
+		t.Errorf("Report(...): want fields updated and the first detection kept, got %+v", status)
+	}
+
+	r.Report(mg, &status, nil)
+	if status != nil {
+		t.Errorf("Report(...): want drift cleared, got %+v", status)
+	}
+}
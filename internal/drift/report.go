@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+)
+
+// ReasonDetected is the reason of the event emitted when drift is detected.
+const ReasonDetected event.Reason = "DriftDetected"
+
+const (
+	// maxFields is the number of differences kept in status.
+	maxFields = 20
+	// maxSummaryPaths is the number of paths named in the summary.
+	maxSummaryPaths = 5
+)
+
+// A Reporter records drift in the status of managed resources.
+type Reporter struct {
+	recorder event.Recorder
+	now      func() time.Time
+}
+
+// NewReporter returns a Reporter that emits events to the supplied recorder.
+func NewReporter(r event.Recorder) *Reporter {
+	return &Reporter{recorder: r, now: time.Now}
+}
+
+// Report sets *status to a summary of the differences, or to nil if there are
+// none. An event is emitted when drift is first detected, i.e. when *status
+// was nil. A nil Reporter updates status without emitting events.
+func (r *Reporter) Report(mg resource.Managed, status **v1alpha1.Drift, diffs []Difference) {
+	if len(diffs) == 0 {
+		*status = nil
+		return
+	}
+
+	d := &v1alpha1.Drift{Summary: Summarize(diffs, maxSummaryPaths)}
+	for i, diff := range diffs {
+		if i == maxFields {
+			break
+		}
+		d.Fields = append(d.Fields, v1alpha1.DriftField{Path: diff.Path, Desired: diff.Desired, Observed: diff.Observed})
+	}
+
+	if prev := *status; prev != nil {
+		d.DetectedAt = prev.DetectedAt
+		*status = d
+		return
+	}
+	now := metav1.Now()
+	if r != nil {
+		now = metav1.NewTime(r.now())
+	}
+	d.DetectedAt = &now
+	*status = d
+	if r != nil {
+		r.recorder.Event(mg, event.Warning(ReasonDetected, errors.New(d.Summary)))
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun reports the changes the provider would make to GoCD instead
+// of making them.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+)
+
+// AnnotationKeyDryRun enables dry run for a managed resource when "true", or
+// disables it when "false" even if the provider runs with --dry-run.
+const AnnotationKeyDryRun = "gocd.crossplane.io/dry-run"
+
+// TypePlannedChange is the type of the condition describing the change a dry
+// run would have made.
+const TypePlannedChange xpv1.ConditionType = "PlannedChange"
+
+// Reasons of the PlannedChange condition and of the events emitted with it.
+const (
+	ReasonWouldCreate xpv1.ConditionReason = "WouldCreate"
+	ReasonWouldUpdate xpv1.ConditionReason = "WouldUpdate"
+	ReasonWouldDelete xpv1.ConditionReason = "WouldDelete"
+	ReasonNoChange    xpv1.ConditionReason = "NoChange"
+	ReasonDisabled    xpv1.ConditionReason = "DryRunDisabled"
+)
+
+// maxFields is the number of differences named in the condition message.
+const maxFields = 5
+
+// A Connector wraps an ExternalConnecter so that the ExternalClients it
+// produces observe managed resources in dry run but do not create, update or
+// delete them in GoCD.
+type Connector struct {
+	next     managed.ExternalConnecter
+	enabled  bool
+	recorder event.Recorder
+}
+
+// NewConnector returns a Connector. enabled sets whether managed resources
+// without the dry run annotation are in dry run.
+func NewConnector(next managed.ExternalConnecter, enabled bool, r event.Recorder) *Connector {
+	return &Connector{next: next, enabled: enabled, recorder: r}
+}
+
+// Connect connects the wrapped ExternalConnecter.
+func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ec, err := c.next.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+	return &external{next: ec, enabled: c.enabled, recorder: c.recorder}, nil
+}
+
+type external struct {
+	next     managed.ExternalClient
+	enabled  bool
+	recorder event.Recorder
+}
+
+// Enabled reports whether a managed resource is in dry run: its annotation
+// decides, and without it the provider wide setting does. An annotation that
+// is not a boolean enables dry run, so a typo never applies changes.
+func Enabled(mg resource.Managed, enabled bool) bool {
+	v, ok := mg.GetAnnotations()[AnnotationKeyDryRun]
+	if !ok {
+		return enabled
+	}
+	b, err := strconv.ParseBool(v)
+	return err != nil || b
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.next.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+	switch {
+	case !Enabled(mg, e.enabled):
+		if mg.GetCondition(TypePlannedChange).Status == corev1.ConditionTrue {
+			mg.SetConditions(condition(corev1.ConditionFalse, ReasonDisabled, ""))
+		}
+	case o.ResourceExists && o.ResourceUpToDate && !meta.WasDeleted(mg):
+		mg.SetConditions(condition(corev1.ConditionFalse, ReasonNoChange, ""))
+	}
+	return o, nil
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if !Enabled(mg, e.enabled) {
+		return e.next.Create(ctx, mg)
+	}
+	e.plan(mg, ReasonWouldCreate, fmt.Sprintf("Dry run: would create %s", mg.GetName()))
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if !Enabled(mg, e.enabled) {
+		return e.next.Update(ctx, mg)
+	}
+	e.plan(mg, ReasonWouldUpdate, "Dry run: would update "+differences(mg))
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	if !Enabled(mg, e.enabled) {
+		return e.next.Delete(ctx, mg)
+	}
+	e.plan(mg, ReasonWouldDelete, fmt.Sprintf("Dry run: would delete %s", meta.GetExternalName(mg)))
+	return managed.ExternalDelete{}, nil
+}
+
+func (e *external) Disconnect(ctx context.Context) error {
+	return e.next.Disconnect(ctx)
+}
+
+// plan records a planned change in the PlannedChange condition. An event is
+// emitted when the planned change differs from the one already recorded, so
+// that a resource waiting in dry run does not emit an event every poll.
+func (e *external) plan(mg resource.Managed, reason xpv1.ConditionReason, msg string) {
+	prev := mg.GetCondition(TypePlannedChange)
+	c := condition(corev1.ConditionTrue, reason, msg)
+	mg.SetConditions(c)
+	if prev.Equal(c) {
+		return
+	}
+	e.recorder.Event(mg, event.Normal(event.Reason(reason), msg))
+}
+
+func condition(s corev1.ConditionStatus, r xpv1.ConditionReason, msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePlannedChange,
+		Status:             s,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// differences describes the drift Observe recorded in status. Credentials are
+// redacted in status, so they are in the description.
+func differences(mg resource.Managed) string {
+	d := &v1alpha1.Drift{}
+	p, err := fieldpath.PaveObject(mg)
+	if err != nil || p.GetValueInto("status.drift", d) != nil || len(d.Fields) == 0 {
+		return mg.GetName()
+	}
+	fields := make([]string, 0, maxFields+1)
+	for i, f := range d.Fields {
+		if i == maxFields {
+			fields = append(fields, fmt.Sprintf("and %d more", len(d.Fields)-maxFields))
+			break
+		}
+		fields = append(fields, fmt.Sprintf("%s: %s -> %s", f.Path, orUnset(f.Observed), orUnset(f.Desired)))
+	}
+	return fmt.Sprintf("%s: %s", mg.GetName(), strings.Join(fields, "; "))
+}
+
+func orUnset(v string) string {
+	if v == "" {
+		return "<unset>"
+	}
+	return v
+}
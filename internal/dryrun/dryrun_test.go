@@ -0,0 +1,177 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+)
+
+// recorder records the events emitted through it.
+type recorder struct{ events []event.Event }
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) WithAnnotations(...string) event.Recorder { return r }
+
+// calls counts the operations made on an external client.
+type calls struct{ create, update, delete int }
+
+func connect(t *testing.T, enabled bool, rec event.Recorder, c *calls, upToDate bool) managed.ExternalClient {
+	t.Helper()
+	next := managed.ExternalConnectorFn(func(context.Context, resource.Managed) (managed.ExternalClient, error) {
+		return &managed.ExternalClientFns{
+			ObserveFn: func(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+				return managed.ExternalObservation{ResourceExists: meta.GetExternalName(mg) != "", ResourceUpToDate: upToDate}, nil
+			},
+			CreateFn: func(context.Context, resource.Managed) (managed.ExternalCreation, error) {
+				c.create++
+				return managed.ExternalCreation{}, nil
+			},
+			UpdateFn: func(context.Context, resource.Managed) (managed.ExternalUpdate, error) {
+				c.update++
+				return managed.ExternalUpdate{}, nil
+			},
+			DeleteFn: func(context.Context, resource.Managed) (managed.ExternalDelete, error) {
+				c.delete++
+				return managed.ExternalDelete{}, nil
+			},
+			DisconnectFn: func(context.Context) error { return nil },
+		}, nil
+	})
+	ec, err := NewConnector(next, enabled, rec).Connect(context.Background(), &v1alpha1.Role{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ec
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		rec, c := &recorder{}, &calls{}
+		ec := connect(t, true, rec, c, false)
+		cr := &v1alpha1.Role{ObjectMeta: metav1.ObjectMeta{Name: "devs"}}
+		for range 2 {
+			if _, err := ec.Create(ctx, cr); err != nil {
+				t.Fatalf("Create(...): %v", err)
+			}
+		}
+		if c.create != 0 {
+			t.Errorf("Create(...): want no role created, got %d", c.create)
+		}
+		if got := cr.GetCondition(TypePlannedChange); got.Status != corev1.ConditionTrue || got.Reason != ReasonWouldCreate {
+			t.Errorf("Create(...): want planned creation, got %+v", got)
+		}
+		if len(rec.events) != 1 || rec.events[0].Reason != event.Reason(ReasonWouldCreate) {
+			t.Errorf("Create(...): want one WouldCreate event, got %+v", rec.events)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		rec, c := &recorder{}, &calls{}
+		ec := connect(t, true, rec, c, false)
+		cr := &v1alpha1.Role{ObjectMeta: metav1.ObjectMeta{Name: "devs"}}
+		meta.SetExternalName(cr, "devs")
+		cr.Status.Drift = &v1alpha1.Drift{Fields: []v1alpha1.DriftField{
+			{Path: "attributes.users", Desired: `["alice","bob"]`, Observed: `["alice"]`},
+			{Path: "attributes.properties[Password].value", Desired: `"<redacted>"`},
+		}}
+		if _, err := ec.Update(ctx, cr); err != nil {
+			t.Fatalf("Update(...): %v", err)
+		}
+		if c.update != 0 {
+			t.Errorf("Update(...): want no role updated, got %d", c.update)
+		}
+		want := `Dry run: would update devs: attributes.users: ["alice"] -> ["alice","bob"]; attributes.properties[Password].value: <unset> -> "<redacted>"`
+		if got := cr.GetCondition(TypePlannedChange); got.Reason != ReasonWouldUpdate || got.Message != want {
+			t.Errorf("Update(...): want message\n%s\ngot %+v", want, got)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		rec, c := &recorder{}, &calls{}
+		ec := connect(t, true, rec, c, true)
+		cr := &v1alpha1.Role{ObjectMeta: metav1.ObjectMeta{Name: "devs"}}
+		meta.SetExternalName(cr, "devs")
+		if _, err := ec.Delete(ctx, cr); err != nil {
+			t.Fatalf("Delete(...): %v", err)
+		}
+		if c.delete != 0 {
+			t.Errorf("Delete(...): want no role deleted, got %d", c.delete)
+		}
+		if got := cr.GetCondition(TypePlannedChange); got.Reason != ReasonWouldDelete {
+			t.Errorf("Delete(...): want planned deletion, got %+v", got)
+		}
+	})
+
+	t.Run("NoChange", func(t *testing.T) {
+		ec := connect(t, true, &recorder{}, &calls{}, true)
+		cr := &v1alpha1.Role{ObjectMeta: metav1.ObjectMeta{Name: "devs"}}
+		meta.SetExternalName(cr, "devs")
+		if _, err := ec.Observe(ctx, cr); err != nil {
+			t.Fatalf("Observe(...): %v", err)
+		}
+		if got := cr.GetCondition(TypePlannedChange); got.Status != corev1.ConditionFalse || got.Reason != ReasonNoChange {
+			t.Errorf("Observe(...): want no planned change, got %+v", got)
+		}
+	})
+
+	t.Run("AnnotationEnables", func(t *testing.T) {
+		c := &calls{}
+		ec := connect(t, false, &recorder{}, c, false)
+		cr := &v1alpha1.Role{ObjectMeta: metav1.ObjectMeta{Name: "devs", Annotations: map[string]string{AnnotationKeyDryRun: "true"}}}
+		if _, err := ec.Create(ctx, cr); err != nil {
+			t.Fatalf("Create(...): %v", err)
+		}
+		if c.create != 0 {
+			t.Errorf("Create(...): want no role created in dry run, got %d", c.create)
+		}
+	})
+
+	t.Run("AnnotationDisables", func(t *testing.T) {
+		c := &calls{}
+		ec := connect(t, true, &recorder{}, c, false)
+		cr := &v1alpha1.Role{ObjectMeta: metav1.ObjectMeta{Name: "devs", Annotations: map[string]string{AnnotationKeyDryRun: "false"}}}
+		cr.SetConditions(xpv1.Condition{Type: TypePlannedChange, Status: corev1.ConditionTrue, Reason: ReasonWouldCreate})
+		meta.SetExternalName(cr, "devs")
+		if _, err := ec.Observe(ctx, cr); err != nil {
+			t.Fatalf("Observe(...): %v", err)
+		}
+		if got := cr.GetCondition(TypePlannedChange); got.Status != corev1.ConditionFalse || got.Reason != ReasonDisabled {
+			t.Errorf("Observe(...): want planned change cleared, got %+v", got)
+		}
+		if _, err := ec.Update(ctx, cr); err != nil {
+			t.Fatalf("Update(...): %v", err)
+		}
+		if c.update != 1 {
+			t.Errorf("Update(...): want role updated, got %d updates", c.update)
+		}
+	})
+}
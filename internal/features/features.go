@@ -29,4 +29,8 @@ const (
 	// Management Policies. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/master/design/design-doc-observe-only-resources.md
 	EnableAlphaManagementPolicies feature.Flag = "EnableAlphaManagementPolicies"
+
+	// EnableDryRun observes managed resources and reports the changes the
+	// provider would make to GoCD without making them.
+	EnableDryRun feature.Flag = "EnableDryRun"
 )
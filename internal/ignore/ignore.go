@@ -0,0 +1,159 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ignore leaves fields managed outside of a managed resource to
+// GoCD.
+package ignore
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// keys are the fields identifying the elements of an array of objects, in
+// order of preference.
+var keys = []string{"name", "key", "id"}
+
+// Apply sets the fields of desired at the supplied paths to the values they
+// have in observed, so that ignored fields compare equal and an update keeps
+// the value GoCD has. A field observed unset is removed from desired.
+//
+// Paths are JSON pointer like paths over the GoCD representation of the
+// resource, e.g. /timer or /stages/build/environment_variables/RELEASE. An
+// element of an array is addressed by its name, key or id, or by its index.
+// desired must be a pointer; observed may be nil, e.g. before the resource
+// exists, in which case desired is left as it is.
+func Apply(desired, observed any, paths []string) error {
+	if len(paths) == 0 || observed == nil || reflect.ValueOf(observed).IsZero() {
+		return nil
+	}
+	d, err := decode(desired)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode desired state")
+	}
+	o, err := decode(observed)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode observed state")
+	}
+	for _, p := range paths {
+		d, _ = set(d, true, o, true, split(p))
+	}
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		return errors.Wrap(err, "cannot encode desired state")
+	}
+	v := reflect.ValueOf(desired).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return errors.Wrap(json.Unmarshal(b, desired), "cannot decode desired state")
+}
+
+// split returns the unescaped segments of a path.
+func split(p string) []string {
+	segs := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, s := range segs {
+		segs[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+	}
+	return segs
+}
+
+// set returns d with the value at the path segs replaced by the value o has
+// there, and whether the result is set. dOK and oOK report whether d and o
+// are set.
+func set(d any, dOK bool, o any, oOK bool, segs []string) (any, bool) {
+	if len(segs) == 0 {
+		return o, oOK
+	}
+	if !oOK && !dOK {
+		return d, dOK
+	}
+
+	da, isArray := d.([]any)
+	oa, oIsArray := o.([]any)
+	if isArray || oIsArray {
+		di, oi := index(da, segs[0]), index(oa, segs[0])
+		if di < 0 && oi < 0 {
+			return d, dOK
+		}
+		var dv, ov any
+		if di >= 0 {
+			dv = da[di]
+		}
+		if oi >= 0 {
+			ov = oa[oi]
+		}
+		v, ok := set(dv, di >= 0, ov, oi >= 0, segs[1:])
+		switch {
+		case ok && di >= 0:
+			da[di] = v
+		case ok:
+			da = append(da, v)
+		case di >= 0:
+			da = slices.Delete(da, di, di+1)
+		}
+		return da, true
+	}
+
+	dm, _ := d.(map[string]any)
+	om, _ := o.(map[string]any)
+	if dm == nil {
+		if om == nil {
+			return d, dOK
+		}
+		dm = map[string]any{}
+	}
+	dv, dHas := dm[segs[0]]
+	ov, oHas := om[segs[0]]
+	if v, ok := set(dv, dHas, ov, oHas, segs[1:]); ok {
+		dm[segs[0]] = v
+	} else {
+		delete(dm, segs[0])
+	}
+	return dm, true
+}
+
+// index returns the index of the element of a addressed by seg, or -1.
+func index(a []any, seg string) int {
+	for i, e := range a {
+		m, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, k := range keys {
+			if id, ok := m[k].(string); ok && id == seg {
+				return i
+			}
+		}
+	}
+	if i, err := strconv.Atoi(seg); err == nil && i >= 0 && i < len(a) {
+		return i
+	}
+	return -1
+}
+
+func decode(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	return out, json.Unmarshal(b, &out)
+}
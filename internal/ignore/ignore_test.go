@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ignore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func TestApply(t *testing.T) {
+	pipeline := func(t *testing.T, s string) *gocd.PipelineConfig {
+		t.Helper()
+		pc := &gocd.PipelineConfig{}
+		if err := json.Unmarshal([]byte(s), pc); err != nil {
+			t.Fatal(err)
+		}
+		return pc
+	}
+
+	cases := map[string]struct {
+		paths    []string
+		desired  string
+		observed string
+		want     string
+	}{
+		"NoPaths": {
+			desired:  `{"name": "up42", "label_template": "${COUNT}"}`,
+			observed: `{"name": "up42", "label_template": "${COUNT}-${git}"}`,
+			want:     `{"name": "up42", "label_template": "${COUNT}"}`,
+		},
+		"Field": {
+			paths:    []string{"/label_template"},
+			desired:  `{"name": "up42", "label_template": "${COUNT}"}`,
+			observed: `{"name": "up42", "label_template": "${COUNT}-${git}"}`,
+			want:     `{"name": "up42", "label_template": "${COUNT}-${git}"}`,
+		},
+		"FieldObservedUnset": {
+			paths:    []string{"/timer"},
+			desired:  `{"name": "up42", "timer": {"spec": "0 0 * * * ?"}}`,
+			observed: `{"name": "up42"}`,
+			want:     `{"name": "up42"}`,
+		},
+		"ElementByName": {
+			paths:    []string{"/environment_variables/RELEASE"},
+			desired:  `{"name": "up42", "environment_variables": [{"name": "A", "value": "1"}, {"name": "RELEASE", "value": "1.0"}]}`,
+			observed: `{"name": "up42", "environment_variables": [{"name": "RELEASE", "value": "1.2"}, {"name": "A", "value": "0"}]}`,
+			want:     `{"name": "up42", "environment_variables": [{"name": "A", "value": "1"}, {"name": "RELEASE", "value": "1.2"}]}`,
+		},
+		"ElementOnlyObserved": {
+			paths:    []string{"/environment_variables/RELEASE"},
+			desired:  `{"name": "up42", "environment_variables": [{"name": "A", "value": "1"}]}`,
+			observed: `{"name": "up42", "environment_variables": [{"name": "RELEASE", "value": "1.2"}]}`,
+			want:     `{"name": "up42", "environment_variables": [{"name": "A", "value": "1"}, {"name": "RELEASE", "value": "1.2"}]}`,
+		},
+		"ElementOnlyDesired": {
+			paths:    []string{"/environment_variables/RELEASE"},
+			desired:  `{"name": "up42", "environment_variables": [{"name": "A", "value": "1"}, {"name": "RELEASE", "value": "1.0"}]}`,
+			observed: `{"name": "up42", "environment_variables": [{"name": "A", "value": "1"}]}`,
+			want:     `{"name": "up42", "environment_variables": [{"name": "A", "value": "1"}]}`,
+		},
+		"NestedByNameAndIndex": {
+			paths:    []string{"/stages/build/jobs/0/timeout"},
+			desired:  `{"name": "up42", "stages": [{"name": "build", "jobs": [{"name": "compile", "timeout": 10}]}]}`,
+			observed: `{"name": "up42", "stages": [{"name": "build", "jobs": [{"name": "compile", "timeout": 30}]}]}`,
+			want:     `{"name": "up42", "stages": [{"name": "build", "jobs": [{"name": "compile", "timeout": 30}]}]}`,
+		},
+		"Missing": {
+			paths:    []string{"/stages/deploy/jobs"},
+			desired:  `{"name": "up42", "stages": [{"name": "build"}]}`,
+			observed: `{"name": "up42", "stages": [{"name": "build"}]}`,
+			want:     `{"name": "up42", "stages": [{"name": "build"}]}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			desired := pipeline(t, tc.desired)
+			if err := Apply(desired, pipeline(t, tc.observed), tc.paths); err != nil {
+				t.Fatalf("Apply(...): %v", err)
+			}
+			if diff := cmp.Diff(pipeline(t, tc.want), desired); diff != "" {
+				t.Errorf("Apply(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("NotObserved", func(t *testing.T) {
+		desired := pipeline(t, `{"name": "up42", "label_template": "${COUNT}"}`)
+		if err := Apply(desired, (*gocd.PipelineConfig)(nil), []string{"/label_template"}); err != nil {
+			t.Fatalf("Apply(...): %v", err)
+		}
+		if diff := cmp.Diff(pipeline(t, `{"name": "up42", "label_template": "${COUNT}"}`), desired); diff != "" {
+			t.Errorf("Apply(...): -want, +got:\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer generates managed resource manifests from the objects of
+// an existing GoCD server, so that they can be adopted by the provider
+// without being recreated.
+package importer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/controller/pipelineconfig"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
+)
+
+const (
+	errListRoles       = "cannot list roles"
+	errListAuthConfigs = "cannot list authorization configurations"
+	errListProfiles    = "cannot list elastic agent profiles"
+	errListPipelines   = "cannot list pipelines"
+	errGetPipeline     = "cannot get pipeline"
+	errRecordSecure    = "cannot record the secure variables of pipeline"
+)
+
+// Options configures the generated manifests.
+type Options struct {
+	// ProviderConfigName is referenced by every manifest. The provider's
+	// default ProviderConfig is used if it is empty.
+	ProviderConfigName string
+
+	// SecretNamespace is the namespace of the Secrets referenced in place of
+	// the values of secure environment variables.
+	SecretNamespace string
+}
+
+// A Manifest is a managed resource generated from a GoCD object.
+type Manifest struct {
+	Object resource.Managed
+
+	// Notes describe what has to be done before the manifest is applied,
+	// e.g. which Secrets must hold the values GoCD does not reveal.
+	Notes []string
+}
+
+// A Result is the outcome of an import.
+type Result struct {
+	Manifests []Manifest
+
+	// Skipped lists the GoCD objects that cannot be managed by the provider,
+	// with the reason why.
+	Skipped []string
+}
+
+// An Importer generates manifests from the objects of a GoCD server.
+type Importer struct {
+	gc    gocd.Client
+	opts  Options
+	names map[string]map[string]bool
+}
+
+// New returns an Importer that reads objects with the supplied client.
+func New(gc gocd.Client, o Options) *Importer {
+	return &Importer{gc: gc, opts: o, names: map[string]map[string]bool{}}
+}
+
+// Import lists every object supported by the provider and maps it to a
+// managed resource whose external name is the object's GoCD identifier.
+func (i *Importer) Import(ctx context.Context) (*Result, error) {
+	res := &Result{}
+
+	roles, err := i.gc.Roles().List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errListRoles)
+	}
+	for _, r := range roles {
+		res.Manifests = append(res.Manifests, i.role(r))
+	}
+
+	authz, err := i.gc.AuthorizationConfigurations().List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errListAuthConfigs)
+	}
+	for _, a := range authz {
+		res.Manifests = append(res.Manifests, i.authorizationConfiguration(a))
+	}
+
+	profiles, err := i.gc.ElasticAgentProfile().List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errListProfiles)
+	}
+	for _, p := range profiles {
+		res.Manifests = append(res.Manifests, i.elasticAgentProfile(p.ElasticAgentProfile))
+	}
+
+	pipelines, err := i.gc.PipelineConfigs().List(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errListPipelines)
+	}
+	for _, ref := range pipelines {
+		pc, _, err := i.gc.PipelineConfigs().Get(ctx, ref.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %s", errGetPipeline, ref.Name)
+		}
+		if pc == nil {
+			// Deleted since it was listed.
+			continue
+		}
+		if pc.Origin != nil && pc.Origin.Type != nil && *pc.Origin.Type == gocd.PipelineConfigOriginTypeConfigRepo {
+			res.Skipped = append(res.Skipped, fmt.Sprintf("pipeline %s: defined in config repository %s", ref.Name, derefOr(pc.Origin.ID, "<unknown>")))
+			continue
+		}
+		if pc.Group == nil {
+			pc.Group = &ref.Group
+		}
+		m, err := i.pipelineConfig(pc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %s", errRecordSecure, ref.Name)
+		}
+		res.Manifests = append(res.Manifests, m)
+	}
+	return res, nil
+}
+
+func (i *Importer) role(r gocd.Role) Manifest {
+	cr := &v1alpha1.Role{
+		Spec: v1alpha1.RoleSpec{
+			ForProvider: v1alpha1.RoleParameters{
+				Name: r.Name,
+				Type: r.Type,
+			},
+		},
+	}
+	var notes []string
+	if r.Attributes != nil {
+		cr.Spec.ForProvider.Attributes = v1alpha1.RoleParametersAttributes{
+			Users:        r.Attributes.Users,
+			AuthConfigID: r.Attributes.AuthConfigID,
+		}
+		cr.Spec.ForProvider.Attributes.Properties, notes = properties(r.Attributes.Properties)
+	}
+	for _, p := range r.Policy {
+		cr.Spec.ForProvider.Policy = append(cr.Spec.ForProvider.Policy, v1alpha1.RoleParametersPolicy{
+			Permission: p.Permission,
+			Action:     p.Action,
+			Type:       p.Type,
+			Resource:   p.Resource,
+		})
+	}
+	i.setMeta(cr, v1alpha1.RoleGroupVersionKind.Kind, r.Name)
+	return Manifest{Object: cr, Notes: notes}
+}
+
+func (i *Importer) authorizationConfiguration(a gocd.AuthorizationConfiguration) Manifest {
+	props, notes := properties(a.Properties)
+	cr := &v1alpha1.AuthorizationConfiguration{
+		Spec: v1alpha1.AuthorizationConfigurationSpec{
+			ForProvider: v1alpha1.AuthorizationConfigurationParameters{
+				ID:                        a.ID,
+				PluginID:                  a.PluginID,
+				AllowOnlyKnowUsersToLogin: a.AllowOnlyKnownUsersToLogin,
+				Properties:                props,
+			},
+		},
+	}
+	i.setMeta(cr, v1alpha1.AuthorizationConfigurationGroupVersionKind.Kind, a.ID)
+	return Manifest{Object: cr, Notes: notes}
+}
+
+func (i *Importer) elasticAgentProfile(p gocd.ElasticAgentProfile) Manifest {
+	kv, notes := properties(p.Properties)
+	props := make([]v1alpha1.ConfigProperty, 0, len(kv))
+	for _, v := range kv {
+		props = append(props, v1alpha1.ConfigProperty{Key: v.Key, Value: v.Value})
+	}
+	cr := &v1alpha1.ElasticAgentProfile{
+		Spec: v1alpha1.ElasticAgentProfileSpec{
+			ForProvider: v1alpha1.ElasticAgentProfileParameters{
+				ID:               p.ID,
+				ClusterProfileID: p.ClusterProfileID,
+				Properties:       props,
+			},
+		},
+	}
+	i.setMeta(cr, v1alpha1.ElasticAgentProfileGroupVersionKind.Kind, p.ID)
+	return Manifest{Object: cr, Notes: notes}
+}
+
+func (i *Importer) pipelineConfig(pc *gocd.PipelineConfig) (Manifest, error) {
+	cr := &v1alpha1.PipelineConfig{}
+	name := i.setMeta(cr, v1alpha1.PipelineConfigGroupVersionKind.Kind, ptr.Deref(pc.Name))
+
+	secret := name + "-secure-variables"
+	var keys []string
+	cr.Spec.ForProvider = pipelineconfig.MapDTOToAPIPipelineConfig(pc, func(scope []string, variable string) *v1alpha1.ValueSource {
+		key := secretKey(append(scope, variable))
+		keys = append(keys, key)
+		return &v1alpha1.ValueSource{
+			SecretKeyRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Name: secret, Namespace: i.opts.SecretNamespace},
+				Key:             key,
+			},
+		}
+	})
+
+	// The encrypted values are recorded so that adopting the pipeline does
+	// not apply its secure variables again.
+	if err := pipelineconfig.RecordEncryptedValues(cr, pc); err != nil {
+		return Manifest{}, err
+	}
+
+	var notes []string
+	if len(keys) > 0 {
+		notes = append(notes, fmt.Sprintf("Secret %s/%s must hold the values of the secure variables %s; they are trusted to be the values GoCD stores", i.opts.SecretNamespace, secret, strings.Join(keys, ", ")))
+	}
+	notes = append(notes, clearMaterialPasswords(cr.Spec.ForProvider.Materials)...)
+	return Manifest{Object: cr, Notes: notes}, nil
+}
+
+// setMeta sets the type, name, external name and provider config of the
+// managed resource and returns its name.
+func (i *Importer) setMeta(mg resource.Managed, kind, id string) string {
+	mg.GetObjectKind().SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(kind))
+	name := i.uniqueName(kind, id)
+	mg.SetName(name)
+	meta.SetExternalName(mg, id)
+	if i.opts.ProviderConfigName != "" {
+		mg.SetProviderConfigReference(&xpv1.Reference{Name: i.opts.ProviderConfigName})
+	}
+	return name
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// uniqueName returns a valid Kubernetes name derived from the GoCD
+// identifier that is not used by another resource of the same kind. GoCD
+// identifiers may contain upper case letters and underscores.
+func (i *Importer) uniqueName(kind, id string) string {
+	base := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(id), "-"), "-.")
+	if base == "" {
+		base = strings.ToLower(kind)
+	}
+	if len(base) > 200 {
+		base = strings.Trim(base[:200], "-.")
+	}
+	if i.names[kind] == nil {
+		i.names[kind] = map[string]bool{}
+	}
+	name := base
+	for n := 2; i.names[kind][name]; n++ {
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+	i.names[kind][name] = true
+	return name
+}
+
+// properties maps plugin properties, keeping the encrypted values GoCD returns
+// for those that hold credentials so that adoption does not reset them. A
+// credential GoCD returns in plain text is removed.
+func properties(in []gocd.ConfigProperty) ([]v1alpha1.KeyValue, []string) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make([]v1alpha1.KeyValue, 0, len(in))
+	var notes []string
+	for _, p := range in {
+		v := p.Value
+		if v != "" && gocd.IsSensitiveKey(p.Key) {
+			v = ""
+			notes = append(notes, fmt.Sprintf("property %s holds a credential and must be set before applying", p.Key))
+		}
+		out = append(out, v1alpha1.KeyValue{Key: p.Key, Value: v, EncryptedValue: p.EncryptedValue})
+	}
+	return out, notes
+}
+
+// clearMaterialPasswords removes plain text material passwords, keeping the
+// encrypted ones GoCD returns so that adoption does not reset them.
+func clearMaterialPasswords(materials []v1alpha1.Material) []string {
+	var notes []string
+	clear := func(name string, pw *string) {
+		if *pw != "" {
+			*pw = ""
+			notes = append(notes, fmt.Sprintf("the password of material %s must be set before applying", name))
+		}
+	}
+	for _, m := range materials {
+		switch {
+		case m.GitAttributes != nil:
+			clear(m.GitAttributes.URL, &m.GitAttributes.Password)
+		case m.SvnAttributes != nil:
+			clear(m.SvnAttributes.URL, &m.SvnAttributes.Password)
+		case m.HgAttributes != nil:
+			clear(m.HgAttributes.URL, &m.HgAttributes.Password)
+		case m.P4Attributes != nil:
+			clear(m.P4Attributes.Port, &m.P4Attributes.Password)
+		case m.TfsAttributes != nil:
+			clear(m.TfsAttributes.URL, &m.TfsAttributes.Password)
+		}
+	}
+	return notes
+}
+
+var invalidKeyChars = regexp.MustCompile(`[^-._a-zA-Z0-9]+`)
+
+// secretKey returns the Secret key of a secure variable, qualified by the
+// stage and job it is defined in.
+func secretKey(path []string) string {
+	parts := make([]string, 0, len(path))
+	for _, p := range path {
+		parts = append(parts, invalidKeyChars.ReplaceAllString(p, "-"))
+	}
+	return strings.Join(parts, ".")
+}
+
+func derefOr(s *string, def string) string {
+	if s == nil {
+		return def
+	}
+	return *s
+}
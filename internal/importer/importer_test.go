@@ -0,0 +1,182 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/controller/pipelineconfig"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/gocd/gocdtest"
+)
+
+func seed(t *testing.T, srv *gocdtest.Server, k gocdtest.Kind, obj any) {
+	t.Helper()
+	if err := srv.Seed(k, obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+
+	seed(t, srv, gocdtest.Roles, gocd.Role{Name: "Build_Admins", Type: "gocd", Attributes: &gocd.RoleAttributes{Users: []string{"alice"}}})
+	seed(t, srv, gocdtest.Roles, gocd.Role{Name: "build-admins", Type: "gocd"})
+	seed(t, srv, gocdtest.AuthConfigs, gocd.AuthorizationConfiguration{
+		ID:       "ldap",
+		PluginID: "cd.go.authentication.ldap",
+		Properties: []gocd.ConfigProperty{
+			{Key: "Url", Value: "ldap://ldap.example.com"},
+			{Key: "Password", Value: "hunter2"},
+			{Key: "ManagerPassword", EncryptedValue: "AES:ghi"},
+		},
+	})
+	seed(t, srv, gocdtest.ElasticProfiles, gocd.ElasticAgentProfile{ID: "k8s", ClusterProfileID: "cluster"})
+	seed(t, srv, gocdtest.Pipelines, map[string]any{
+		"name":           "up42",
+		"group":          "first",
+		"label_template": "${COUNT}",
+		"origin":         map[string]any{"type": "gocd"},
+		"environment_variables": []any{
+			map[string]any{"name": "PLAIN", "value": "visible", "secure": false},
+			map[string]any{"name": "TOKEN", "encrypted_value": "AES:abc", "secure": true},
+		},
+		"materials": []any{
+			map[string]any{"type": "git", "attributes": map[string]any{"url": "https://example.com/up42.git", "branch": "main"}},
+		},
+		"stages": []any{map[string]any{
+			"name": "build",
+			"jobs": []any{map[string]any{
+				"name": "compile",
+				"environment_variables": []any{
+					map[string]any{"name": "DB_PASSWORD", "encrypted_value": "AES:def", "secure": true},
+				},
+				"tasks": []any{map[string]any{"type": "exec", "attributes": map[string]any{"command": "make", "run_if": []any{"passed"}}}},
+			}},
+		}},
+	})
+	seed(t, srv, gocdtest.Pipelines, map[string]any{
+		"name":   "from-repo",
+		"group":  "first",
+		"origin": map[string]any{"type": "config_repo", "id": "repo1"},
+	})
+
+	gc, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	res, err := New(gc, Options{ProviderConfigName: "gocd", SecretNamespace: "ci"}).Import(context.Background())
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+
+	names := map[string]string{}
+	for _, m := range res.Manifests {
+		if got := m.Object.GetProviderConfigReference(); got == nil || got.Name != "gocd" {
+			t.Errorf("%s: want provider config gocd, got %v", m.Object.GetName(), got)
+		}
+		names[m.Object.GetName()+"/"+m.Object.GetObjectKind().GroupVersionKind().Kind] = meta.GetExternalName(m.Object)
+	}
+	want := map[string]string{
+		"build-admins/Role":               "Build_Admins",
+		"build-admins-2/Role":             "build-admins",
+		"ldap/AuthorizationConfiguration": "ldap",
+		"k8s/ElasticAgentProfile":         "k8s",
+		"up42/PipelineConfig":             "up42",
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("Import: -want names and external names, +got:\n%s", diff)
+	}
+	if len(res.Skipped) != 1 || !strings.Contains(res.Skipped[0], "from-repo") {
+		t.Errorf("Import: want the config repo pipeline skipped, got %v", res.Skipped)
+	}
+
+	for _, m := range res.Manifests {
+		switch cr := m.Object.(type) {
+		case *v1alpha1.AuthorizationConfiguration:
+			props := cr.Spec.ForProvider.Properties
+			want := []v1alpha1.KeyValue{
+				{Key: "Url", Value: "ldap://ldap.example.com"},
+				{Key: "Password"},
+				{Key: "ManagerPassword", EncryptedValue: "AES:ghi"},
+			}
+			if diff := cmp.Diff(want, props); diff != "" {
+				t.Errorf("AuthorizationConfiguration: want the plain password cleared and the encrypted one kept, -want, +got:\n%s", diff)
+			}
+			if len(m.Notes) != 1 || !strings.Contains(m.Notes[0], "Password ") {
+				t.Errorf("AuthorizationConfiguration: want a note about the plain password only, got %v", m.Notes)
+			}
+		case *v1alpha1.PipelineConfig:
+			fp := cr.Spec.ForProvider
+			if fp.Group != "first" || fp.LabelTemplate != "${COUNT}" || len(fp.Materials) != 1 || fp.Materials[0].GitAttributes == nil {
+				t.Errorf("PipelineConfig: unexpected parameters %+v", fp)
+			}
+			if fp.EnvironmentVariables[0].Value != "visible" {
+				t.Errorf("PipelineConfig: want plain variables kept, got %+v", fp.EnvironmentVariables[0])
+			}
+			ref := fp.EnvironmentVariables[1].ValueFrom
+			if ref == nil || ref.SecretKeyRef == nil || ref.SecretKeyRef.Name != "up42-secure-variables" || ref.SecretKeyRef.Namespace != "ci" || ref.SecretKeyRef.Key != "TOKEN" {
+				t.Errorf("PipelineConfig: want secure variable read from a Secret, got %+v", ref)
+			}
+			jobRef := fp.Stages[0].Jobs[0].EnvironmentVariables[0].ValueFrom
+			if jobRef == nil || jobRef.SecretKeyRef == nil || jobRef.SecretKeyRef.Key != "build.compile.DB_PASSWORD" {
+				t.Errorf("PipelineConfig: want job variable qualified by stage and job, got %+v", jobRef)
+			}
+			var recorded map[string]struct{ Hash, EncryptedValue string }
+			if err := json.Unmarshal([]byte(cr.GetAnnotations()[pipelineconfig.AnnotationKeySecureVariables]), &recorded); err != nil {
+				t.Fatalf("PipelineConfig: cannot decode the secure variables annotation: %v", err)
+			}
+			wantRecorded := map[string]struct{ Hash, EncryptedValue string }{
+				"pipeline.TOKEN":                {EncryptedValue: "AES:abc"},
+				"job.build.compile.DB_PASSWORD": {EncryptedValue: "AES:def"},
+			}
+			if diff := cmp.Diff(wantRecorded, recorded); diff != "" {
+				t.Errorf("PipelineConfig: want the encrypted values recorded without hashes, -want, +got:\n%s", diff)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, res.Manifests); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+	for _, secret := range []string{"hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Write: output contains secret %q", secret)
+		}
+	}
+	for _, s := range []string{
+		"apiVersion: config.gocd.crossplane.io/v1alpha1",
+		"crossplane.io/external-name: Build_Admins",
+		"encryptedValue: AES:ghi",
+		"# TODO: Secret ci/up42-secure-variables must hold the values of the secure variables TOKEN, build.compile.DB_PASSWORD",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Write: output does not contain %q", s)
+		}
+	}
+	if strings.Contains(out, "status:") || strings.Contains(out, "creationTimestamp") {
+		t.Errorf("Write: output contains status or server generated metadata:\n%s", out)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// Write writes the manifests as a multi-document YAML stream. The notes of
+// each manifest precede it as comments. Status and server generated metadata
+// are left out.
+func Write(w io.Writer, manifests []Manifest) error {
+	for _, m := range manifests {
+		b, err := manifestYAML(m)
+		if err != nil {
+			return errors.Wrapf(err, "cannot serialize %s %s", m.Object.GetObjectKind().GroupVersionKind().Kind, m.Object.GetName())
+		}
+		if _, err := fmt.Fprintln(w, "---"); err != nil {
+			return err
+		}
+		for _, n := range m.Notes {
+			if _, err := fmt.Fprintf(w, "# TODO: %s\n", n); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func manifestYAML(m Manifest) ([]byte, error) {
+	b, err := json.Marshal(m.Object)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	delete(obj, "status")
+	if md, ok := obj["metadata"].(map[string]any); ok {
+		delete(md, "creationTimestamp")
+	}
+	return yaml.Marshal(obj)
+}
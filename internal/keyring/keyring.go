@@ -0,0 +1,175 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package keyring hashes secret values with keys of the provider installation,
+// so that the hashes recorded on managed resources cannot be used to guess the
+// values they were computed from.
+package keyring
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SecretName is the name of the Secret holding the keys.
+	SecretName = "provider-gocd-hash-keys"
+
+	// KeyCurrent is the key of the Secret naming the key new hashes are
+	// computed with. Every other key of the Secret holds a key, by its name.
+	KeyCurrent = "current"
+
+	// prefix starts the hashes computed by a Keyring, followed by the name
+	// of the key and the hex encoded HMAC-SHA256.
+	prefix = "hmac-sha256:"
+
+	initialKey = "1"
+	keySize    = 32
+
+	errGetSecret    = "cannot get hash keys secret"
+	errCreateSecret = "cannot create hash keys secret"
+	errNoCurrentKey = "hash keys secret has no current key"
+)
+
+// A Keyring hashes values with HMAC-SHA256. New hashes use the current key;
+// hashes computed with an older key, or plain SHA-256 hashes recorded before
+// values were keyed, are still verified so they can be migrated.
+type Keyring struct {
+	current string
+	keys    map[string][]byte
+}
+
+// New returns a Keyring hashing with the named current key.
+func New(current string, keys map[string][]byte) (*Keyring, error) {
+	if len(keys[current]) == 0 {
+		return nil, errors.New(errNoCurrentKey)
+	}
+	return &Keyring{current: current, keys: keys}, nil
+}
+
+// Hash returns the hash of a value with the current key.
+func (k *Keyring) Hash(value string) string {
+	return prefix + k.current + ":" + k.sum(k.keys[k.current], value)
+}
+
+// Verify returns whether hash is the hash of value, computed with any key of
+// the Keyring or as a plain SHA-256.
+func (k *Keyring) Verify(value, hash string) bool {
+	name, sum, keyed := parse(hash)
+	if !keyed {
+		plain := sha256.Sum256([]byte(value))
+		return hmac.Equal([]byte(hex.EncodeToString(plain[:])), []byte(hash))
+	}
+	key, ok := k.keys[name]
+	if !ok {
+		return false
+	}
+	return hmac.Equal([]byte(k.sum(key, value)), []byte(sum))
+}
+
+// Current returns whether hash was computed with the current key. A hash that
+// verifies but is not current should be replaced by Hash.
+func (k *Keyring) Current(hash string) bool {
+	name, _, keyed := parse(hash)
+	return keyed && name == k.current
+}
+
+func (k *Keyring) sum(key []byte, value string) string {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(value))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+func parse(hash string) (name, sum string, keyed bool) {
+	rest, ok := strings.CutPrefix(hash, prefix)
+	if !ok {
+		return "", "", false
+	}
+	name, sum, ok = strings.Cut(rest, ":")
+	return name, sum, ok
+}
+
+// A Source reads the Keyring of the provider from a Secret the provider owns.
+//
+// The Secret is created with a random key when it does not exist. To rotate
+// keys add a key to the Secret and name it in its current key: hashes are
+// migrated to the new key as managed resources are reconciled, after which
+// the old key may be removed.
+type Source struct {
+	secret types.NamespacedName
+}
+
+// DefaultSource is the Source of the provider's controllers. The provider
+// replaces it before setting up controllers to read keys from its namespace.
+var DefaultSource = NewSource("crossplane-system")
+
+// NewSource returns a Source reading the keys Secret in the supplied
+// namespace.
+func NewSource(namespace string) *Source {
+	return &Source{secret: types.NamespacedName{Namespace: namespace, Name: SecretName}}
+}
+
+// Keyring returns the Keyring of the keys Secret, creating the Secret if it
+// does not exist.
+func (s *Source) Keyring(ctx context.Context, kube client.Client) (*Keyring, error) {
+	sec := &corev1.Secret{}
+	err := kube.Get(ctx, s.secret, sec)
+	switch {
+	case kerrors.IsNotFound(err):
+		if sec, err = s.create(ctx, kube); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, errors.Wrap(err, errGetSecret)
+	}
+
+	keys := make(map[string][]byte, len(sec.Data))
+	for name, key := range sec.Data {
+		if name != KeyCurrent {
+			keys[name] = key
+		}
+	}
+	return New(string(sec.Data[KeyCurrent]), keys)
+}
+
+func (s *Source) create(ctx context.Context, kube client.Client) (*corev1.Secret, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, errCreateSecret)
+	}
+	sec := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: s.secret.Namespace, Name: s.secret.Name},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{KeyCurrent: []byte(initialKey), initialKey: key},
+	}
+	if err := kube.Create(ctx, sec); err != nil {
+		// Another reconcile created the Secret first; its keys are read on
+		// the next attempt.
+		return nil, errors.Wrap(err, errCreateSecret)
+	}
+	return sec, nil
+}
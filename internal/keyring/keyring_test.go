@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package keyring
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKeyring(t *testing.T) {
+	old, err := New("1", map[string][]byte{"1": []byte("old")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := New("2", map[string][]byte{"1": []byte("old"), "2": []byte("new")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := sha256.Sum256([]byte("hunter2"))
+
+	cases := map[string]struct {
+		hash        string
+		wantVerify  bool
+		wantCurrent bool
+	}{
+		"Current":          {hash: k.Hash("hunter2"), wantVerify: true, wantCurrent: true},
+		"PreviousKey":      {hash: old.Hash("hunter2"), wantVerify: true},
+		"Plain":            {hash: hex.EncodeToString(plain[:]), wantVerify: true},
+		"OtherValue":       {hash: k.Hash("hunter3"), wantCurrent: true},
+		"UnknownKey":       {hash: strings.Replace(k.Hash("hunter2"), ":2:", ":3:", 1)},
+		"PlainOtherValue":  {hash: strings.Repeat("0", 64)},
+		"KeyedWithoutName": {hash: "hmac-sha256:abc"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := k.Verify("hunter2", tc.hash); got != tc.wantVerify {
+				t.Errorf("Verify(...): want %t, got %t", tc.wantVerify, got)
+			}
+			if got := k.Current(tc.hash); got != tc.wantCurrent {
+				t.Errorf("Current(...): want %t, got %t", tc.wantCurrent, got)
+			}
+		})
+	}
+
+	if h := k.Hash("hunter2"); strings.Contains(h, hex.EncodeToString(plain[:])) || h == old.Hash("hunter2") {
+		t.Errorf("Hash(...): want a keyed hash, got %s", h)
+	}
+	if _, err := New("2", map[string][]byte{"1": []byte("old")}); err == nil {
+		t.Errorf("New(...): want an error for a missing current key")
+	}
+}
+
+func TestSource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CreatesSecret", func(t *testing.T) {
+		kube := fake.NewClientBuilder().Build()
+		s := NewSource("crossplane-system")
+		k, err := s.Keyring(ctx, kube)
+		if err != nil {
+			t.Fatalf("Keyring(...): %v", err)
+		}
+
+		sec := &corev1.Secret{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: "crossplane-system", Name: SecretName}, sec); err != nil {
+			t.Fatalf("Keyring(...): want keys secret created: %v", err)
+		}
+		if len(sec.Data["1"]) != keySize || string(sec.Data[KeyCurrent]) != "1" {
+			t.Errorf("Keyring(...): want a random current key, got %v", sec.Data)
+		}
+
+		again, err := s.Keyring(ctx, kube)
+		if err != nil {
+			t.Fatalf("Keyring(...): %v", err)
+		}
+		if k.Hash("hunter2") != again.Hash("hunter2") {
+			t.Errorf("Keyring(...): want the created key reused")
+		}
+	})
+
+	t.Run("ReadsRotatedKeys", func(t *testing.T) {
+		kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "gocd", Name: SecretName},
+			Data:       map[string][]byte{KeyCurrent: []byte("2"), "1": []byte("old"), "2": []byte("new")},
+		}).Build()
+		k, err := NewSource("gocd").Keyring(ctx, kube)
+		if err != nil {
+			t.Fatalf("Keyring(...): %v", err)
+		}
+		old, _ := New("1", map[string][]byte{"1": []byte("old")})
+		if !k.Verify("hunter2", old.Hash("hunter2")) || !k.Current(k.Hash("hunter2")) || !strings.Contains(k.Hash("hunter2"), ":2:") {
+			t.Errorf("Keyring(...): want hashes with key 2 and key 1 verified")
+		}
+	})
+
+	t.Run("NoCurrentKey", func(t *testing.T) {
+		kube := fake.NewClientBuilder().WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "gocd", Name: SecretName},
+			Data:       map[string][]byte{KeyCurrent: []byte("2"), "1": []byte("old")},
+		}).Build()
+		if _, err := NewSource("gocd").Keyring(ctx, kube); err == nil {
+			t.Errorf("Keyring(...): want an error for a missing current key")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lateinit fills unset fields of a managed resource's spec with the
+// values observed on the GoCD server.
+package lateinit
+
+// Value sets *dst to from if *dst is the zero value of T. It reports whether
+// *dst was changed.
+func Value[T comparable](dst *T, from T) bool {
+	var zero T
+	if *dst != zero || from == zero {
+		return false
+	}
+	*dst = from
+	return true
+}
+
+// Slice sets *dst to from if *dst is empty. It reports whether *dst was
+// changed.
+func Slice[T any](dst *[]T, from []T) bool {
+	if len(*dst) > 0 || len(from) == 0 {
+		return false
+	}
+	*dst = from
+	return true
+}
+
+// Any reports whether any of the fields was late initialized. Use it to
+// evaluate every initializer instead of stopping at the first that changed.
+func Any(changed ...bool) bool {
+	for _, c := range changed {
+		if c {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lateinit
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValue(t *testing.T) {
+	cases := map[string]struct {
+		dst, from   string
+		want        string
+		wantChanged bool
+	}{
+		"Unset":     {dst: "", from: "${COUNT}", want: "${COUNT}", wantChanged: true},
+		"Set":       {dst: "${COUNT}-1", from: "${COUNT}", want: "${COUNT}-1"},
+		"EmptyFrom": {dst: "", from: "", want: ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.dst
+			if changed := Value(&got, tc.from); changed != tc.wantChanged {
+				t.Errorf("Value(...): want changed %v, got %v", tc.wantChanged, changed)
+			}
+			if got != tc.want {
+				t.Errorf("Value(...): want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	cases := map[string]struct {
+		dst, from   []string
+		want        []string
+		wantChanged bool
+	}{
+		"Unset":     {from: []string{"alice"}, want: []string{"alice"}, wantChanged: true},
+		"Empty":     {dst: []string{}, from: []string{"alice"}, want: []string{"alice"}, wantChanged: true},
+		"Set":       {dst: []string{"bob"}, from: []string{"alice"}, want: []string{"bob"}},
+		"EmptyFrom": {dst: nil, from: []string{}, want: nil},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.dst
+			if changed := Slice(&got, tc.from); changed != tc.wantChanged {
+				t.Errorf("Slice(...): want changed %v, got %v", tc.wantChanged, changed)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Slice(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
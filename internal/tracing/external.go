@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/marquesgui/provider-gocd/internal/controller/helper"
+)
+
+// A Connector wraps an ExternalConnecter so that Connect and every operation
+// of the ExternalClients it produces are traced.
+type Connector struct {
+	kind string
+	next managed.ExternalConnecter
+}
+
+// NewConnector returns a Connector for managed resources of the supplied kind.
+func NewConnector(kind string, next managed.ExternalConnecter) *Connector {
+	return &Connector{kind: kind, next: next}
+}
+
+// Connect traces the wrapped Connect, which resolves the ProviderConfig and
+// its credentials.
+func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	ctx, span := start(ctx, c.kind, "Connect", mg)
+	ec, err := c.next.Connect(ctx, mg)
+	End(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &external{kind: c.kind, next: ec}, nil
+}
+
+type external struct {
+	kind string
+	next managed.ExternalClient
+}
+
+func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := start(ctx, e.kind, "Observe", mg)
+	o, err := e.next.Observe(ctx, mg)
+	span.SetAttributes(
+		attribute.Bool("gocd.resource.exists", o.ResourceExists),
+		attribute.Bool("gocd.resource.up_to_date", o.ResourceUpToDate),
+	)
+	finish(span, mg, err)
+	return o, err
+}
+
+func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := start(ctx, e.kind, "Create", mg)
+	c, err := e.next.Create(ctx, mg)
+	finish(span, mg, err)
+	return c, err
+}
+
+func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := start(ctx, e.kind, "Update", mg)
+	u, err := e.next.Update(ctx, mg)
+	finish(span, mg, err)
+	return u, err
+}
+
+func (e *external) Delete(ctx context.Context, mg resource.Managed) (managed.ExternalDelete, error) {
+	ctx, span := start(ctx, e.kind, "Delete", mg)
+	d, err := e.next.Delete(ctx, mg)
+	finish(span, mg, err)
+	return d, err
+}
+
+func (e *external) Disconnect(ctx context.Context) error {
+	return e.next.Disconnect(ctx)
+}
+
+func start(ctx context.Context, kind, op string, mg resource.Managed) (context.Context, trace.Span) {
+	return Start(ctx, kind+"."+op,
+		AttrKind.String(kind),
+		AttrName.String(mg.GetName()),
+		AttrExternalName.String(meta.GetExternalName(mg)),
+	)
+}
+
+// finish records attributes only known once the operation completed, such as
+// the external name assigned on creation and the ETag last seen.
+func finish(span trace.Span, mg resource.Managed, err error) {
+	span.SetAttributes(AttrExternalName.String(meta.GetExternalName(mg)))
+	if etag := helper.GetETag(mg); etag != "" {
+		span.SetAttributes(AttrETag.String(etag))
+	}
+	End(span, err)
+}
@@ -0,0 +1,103 @@
+/*
+Package tracing
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer used by the provider's controllers.
+const TracerName = "github.com/marquesgui/provider-gocd"
+
+// Span attribute keys shared by the provider's spans.
+const (
+	AttrKind         = attribute.Key("gocd.resource.kind")
+	AttrName         = attribute.Key("gocd.resource.name")
+	AttrExternalName = attribute.Key("gocd.resource.external_name")
+	AttrETag         = attribute.Key("gocd.etag")
+)
+
+// Options configure the OTLP exporter.
+type Options struct {
+	// Endpoint of the OTLP gRPC collector. If empty the exporter honours the
+	// standard OTEL_EXPORTER_OTLP_* environment variables.
+	Endpoint string
+	// Insecure disables TLS when connecting to the collector.
+	Insecure bool
+	// ServiceName reported with every span.
+	ServiceName string
+	// Version of the provider reported with every span.
+	Version string
+}
+
+// Setup installs a global tracer provider that exports spans over OTLP. The
+// returned function flushes and stops the exporter.
+func Setup(ctx context.Context, o Options) (func(context.Context) error, error) {
+	opts := []otlptracegrpc.Option{}
+	if o.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(o.Endpoint))
+	}
+	if o.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exp, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create OTLP trace exporter")
+	}
+	return Install(sdktrace.NewBatchSpanProcessor(exp), o), nil
+}
+
+// Install registers a global tracer provider that sends spans to the supplied
+// processor. Tests use it with an in-memory exporter.
+func Install(sp sdktrace.SpanProcessor, o Options) func(context.Context) error {
+	res := resource.NewSchemaless(
+		attribute.String("service.name", o.ServiceName),
+		attribute.String("service.version", o.Version),
+	)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sp),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown
+}
+
+// Start starts a span using the provider's tracer. It is a no-op unless a
+// tracer provider has been installed.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
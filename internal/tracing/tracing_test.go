@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/marquesgui/provider-gocd/apis/config/v1alpha1"
+	"github.com/marquesgui/provider-gocd/internal/controller/helper"
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func TestConnectorSpans(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	shutdown := Install(sdktrace.NewSimpleSpanProcessor(exp), Options{ServiceName: "test"})
+	defer shutdown(context.Background()) //nolint:errcheck
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"etag-1"`)
+		fmt.Fprintln(w, `{"name": "admins", "type": "gocd"}`)
+	}))
+	defer ts.Close()
+	gc, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+
+	errBoom := errors.New("boom")
+	c := NewConnector(v1alpha1.RoleKind, managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+		return &managed.ExternalClientFns{
+			ObserveFn: func(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+				_, etag, err := gc.Roles().Get(ctx, "admins")
+				helper.KeepETag(mg, etag)
+				return managed.ExternalObservation{ResourceExists: true}, err
+			},
+			DeleteFn: func(_ context.Context, _ resource.Managed) (managed.ExternalDelete, error) {
+				return managed.ExternalDelete{}, errBoom
+			},
+		}, nil
+	}))
+
+	cr := &v1alpha1.Role{ObjectMeta: metav1.ObjectMeta{Name: "admins-role"}}
+	meta.SetExternalName(cr, "admins")
+
+	ctx := context.Background()
+	ec, err := c.Connect(ctx, cr)
+	if err != nil {
+		t.Fatalf("Connect(...): unexpected error: %v", err)
+	}
+	if _, err := ec.Observe(ctx, cr); err != nil {
+		t.Fatalf("Observe(...): unexpected error: %v", err)
+	}
+	if _, err := ec.Delete(ctx, cr); !errors.Is(err, errBoom) {
+		t.Fatalf("Delete(...): want %v, got %v", errBoom, err)
+	}
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, s := range exp.GetSpans() {
+		spans[s.Name] = s
+	}
+	for _, name := range []string{"Role.Connect", "Role.Observe", "Role.Delete", "GoCD GET roles"} {
+		if _, ok := spans[name]; !ok {
+			t.Fatalf("span %q was not recorded, got %v", name, exp.GetSpans())
+		}
+	}
+
+	observe, call := spans["Role.Observe"], spans["GoCD GET roles"]
+	if call.Parent.SpanID() != observe.SpanContext.SpanID() {
+		t.Errorf("GoCD API span is not a child of the Observe span")
+	}
+	wantAttrs := []attribute.KeyValue{
+		AttrName.String("admins-role"),
+		AttrExternalName.String("admins"),
+		AttrETag.String(`"etag-1"`),
+	}
+	for _, want := range wantAttrs {
+		if !hasAttr(observe.Attributes, want) {
+			t.Errorf("Observe span: missing attribute %v in %v", want, observe.Attributes)
+		}
+	}
+	if !hasAttr(call.Attributes, attribute.Int("http.response.status_code", http.StatusOK)) {
+		t.Errorf("GoCD API span: missing status code in %v", call.Attributes)
+	}
+	if spans["Role.Delete"].Status.Description != errBoom.Error() {
+		t.Errorf("Delete span: want error status %q, got %q", errBoom.Error(), spans["Role.Delete"].Status.Description)
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, a := range attrs {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
@@ -1,34 +1,39 @@
 package cmp
 
+import (
+	"cmp"
+	"slices"
+)
+
 type Equatable[T any] interface {
 	Equal(T) bool
 }
 
 // SlicesEqualUnordered compares two slices of Equatable items for equality, regardless of order.
+// Items are matched by the key getKeyFunc returns; items sharing a key are matched in order.
 func SlicesEqualUnordered[T Equatable[T]](a, b []T, getKeyFunc func(T) string) bool {
 	if len(a) != len(b) {
 		return false
 	}
 
-	keyValue := make(map[string]T, len(a))
-	keyCount := make(map[string]int, len(a))
-
+	byKey := make(map[string][]T, len(a))
 	for _, v := range a {
 		key := getKeyFunc(v)
-		keyValue[key] = v
-		keyCount[key]++
+		byKey[key] = append(byKey[key], v)
 	}
 
 	for _, v := range b {
 		key := getKeyFunc(v)
-		if keyCount[key] == 0 || !keyValue[key].Equal(v) {
+		candidates := byKey[key]
+		if len(candidates) == 0 || !candidates[0].Equal(v) {
 			return false
 		}
-		keyCount[key]--
+		byKey[key] = candidates[1:]
 	}
 	return true
 }
 
+// SliceEqualOrdered compares two slices of Equatable items for equality, in order.
 func SliceEqualOrdered[T Equatable[T]](a, b []T) bool {
 	if len(a) != len(b) {
 		return false
@@ -49,3 +54,14 @@ func PtrEqual[T comparable](a, b *T) bool {
 	}
 	return *a == *b
 }
+
+// ValuesEqualUnordered compares two slices of values for equality, regardless of order.
+func ValuesEqualUnordered[T cmp.Ordered](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -0,0 +1,44 @@
+package cmp
+
+import "testing"
+
+type item struct {
+	key   string
+	value int
+}
+
+func (i item) Equal(o item) bool { return i == o }
+
+func TestSlicesEqualUnordered(t *testing.T) {
+	key := func(i item) string { return i.key }
+	cases := map[string]struct {
+		a, b []item
+		want bool
+	}{
+		"Reordered":        {a: []item{{"a", 1}, {"b", 2}}, b: []item{{"b", 2}, {"a", 1}}, want: true},
+		"DifferentValue":   {a: []item{{"a", 1}, {"b", 2}}, b: []item{{"b", 2}, {"a", 3}}},
+		"DifferentLength":  {a: []item{{"a", 1}}, b: []item{{"a", 1}, {"a", 1}}},
+		"DuplicateKeys":    {a: []item{{"a", 1}, {"a", 2}}, b: []item{{"a", 1}, {"a", 2}}, want: true},
+		"DuplicateKeysSet": {a: []item{{"a", 1}, {"a", 2}}, b: []item{{"a", 2}, {"a", 2}}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := SlicesEqualUnordered(tc.a, tc.b, key); got != tc.want {
+				t.Errorf("SlicesEqualUnordered(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestValuesEqualUnordered(t *testing.T) {
+	a := []string{"linux", "docker"}
+	if !ValuesEqualUnordered(a, []string{"docker", "linux"}) {
+		t.Errorf("ValuesEqualUnordered(...): want reordered values equal")
+	}
+	if ValuesEqualUnordered(a, []string{"linux", "linux"}) {
+		t.Errorf("ValuesEqualUnordered(...): want different values not equal")
+	}
+	if a[0] != "linux" {
+		t.Errorf("ValuesEqualUnordered(...): must not sort its arguments, got %v", a)
+	}
+}
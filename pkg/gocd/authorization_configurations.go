@@ -11,6 +11,8 @@ import (
 
 // AuthorizationConfigurationsService defines methods for GoCD Authorization Configurations.
 type AuthorizationConfigurationsService interface {
+	// List returns all authorization configurations.
+	List(ctx context.Context) ([]AuthorizationConfiguration, error)
 	// Get returns an authorization configuration by id.
 	Get(ctx context.Context, id string) (*AuthorizationConfiguration, string, error)
 	// Create creates a new authorization configuration.
@@ -42,8 +44,17 @@ type AuthorizationConfiguration struct {
 	Links                      *HALLinks        `json:"_links,omitempty"`
 }
 
+func (s *authorizationConfigurationsService) List(ctx context.Context) ([]AuthorizationConfiguration, error) {
+	configs, err := listEmbedded[AuthorizationConfiguration](ctx, s.c, servicePath, ServiceAuthorizationConfigurations, "auth_configs")
+	if err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to list the authorization configurations")
+	}
+	return configs, nil
+}
+
 func (s *authorizationConfigurationsService) Get(ctx context.Context, id string) (*AuthorizationConfiguration, string, error) {
-	resp, err := s.c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s", servicePath, url.PathEscape(id)), acceptAuthzCfg, nil, nil)
+	path := fmt.Sprintf("%s/%s", servicePath, url.PathEscape(id))
+	resp, err := s.c.doVersioned(ctx, http.MethodGet, path, ServiceAuthorizationConfigurations, nil, nil)
 	if err != nil {
 		if IsNotFound(err) {
 			return nil, "", nil
@@ -59,7 +70,7 @@ func (s *authorizationConfigurationsService) Get(ctx context.Context, id string)
 }
 
 func (s *authorizationConfigurationsService) Create(ctx context.Context, cfg AuthorizationConfiguration) (*AuthorizationConfiguration, string, error) {
-	resp, err := s.c.do(ctx, http.MethodPost, servicePath, acceptAuthzCfg, nil, cfg)
+	resp, err := s.c.doVersioned(ctx, http.MethodPost, servicePath, ServiceAuthorizationConfigurations, nil, cfg)
 	if err != nil {
 		return nil, "", err
 	}
@@ -76,7 +87,11 @@ func (s *authorizationConfigurationsService) Update(ctx context.Context, id stri
 	headers := map[string]string{
 		"If-Match": etag,
 	}
-	resp, err := s.c.do(ctx, http.MethodPut, path, acceptAuthzCfg, headers, cfg)
+	body, err := s.c.overlayDocument(ctx, path, ServiceAuthorizationConfigurations, cfg)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "cannot update authorization configuration")
+	}
+	resp, err := s.c.doVersioned(ctx, http.MethodPut, path, ServiceAuthorizationConfigurations, headers, body)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "cannot update authorization configuration")
 	}
@@ -93,7 +108,7 @@ func (s *authorizationConfigurationsService) Delete(ctx context.Context, id stri
 	headers := map[string]string{
 		"If-Match": etag,
 	}
-	resp, err := s.c.do(ctx, http.MethodDelete, path, acceptAuthzCfg, headers, nil)
+	resp, err := s.c.doVersioned(ctx, http.MethodDelete, path, ServiceAuthorizationConfigurations, headers, nil)
 	if err != nil {
 		return errors.Wrap(err, "cannot delete authorization configuration")
 	}
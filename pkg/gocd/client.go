@@ -9,13 +9,18 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config holds parameters to connect to a GoCD server.
 type Config struct {
+	Name      string // Name of the ProviderConfig, labelling the client's metrics
 	BaseURL   string // e.g., https://gocd.example.com/go/api
 	Username  string
 	Password  string
@@ -23,6 +28,25 @@ type Config struct {
 	Insecure  bool   // Skip TLS verification
 	UserAgent string // Optional custom user agent
 	Timeout   time.Duration
+
+	RequestsPerSecond float64 // Client-side rate limit; zero disables it
+	Burst             int     // Requests allowed above RequestsPerSecond at once
+	MaxInFlight       int     // Maximum concurrent requests; zero means unlimited
+
+	ProxyURL      string   // Proxy to reach the server through; empty uses HTTP_PROXY/HTTPS_PROXY
+	ProxyUsername string   // Proxy basic auth user, used with ProxyURL
+	ProxyPassword string   // Proxy basic auth password, used with ProxyURL
+	NoProxy       []string // Hosts, domains, IPs or CIDRs reached without the proxy
+
+	// Logger, if set, logs every request and response with credentials
+	// redacted. Bodies are truncated at MaxLogBodyBytes, or at
+	// DefaultMaxLogBodyBytes if it is zero.
+	Logger          logging.Logger
+	MaxLogBodyBytes int
+
+	// WrapTransport, if set, wraps the transport used to reach the server, e.g.
+	// with a Recorder or Replayer.
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // Client is a minimal interface for interacting with GoCD API features used by this provider.
@@ -32,6 +56,8 @@ type Client interface {
 	Roles() RolesService
 	PipelineConfigs() PipelineConfigsService
 	ElasticAgentProfile() ElasticAgentProfileService
+	Server() ServerService
+	Encryption() EncryptionService
 }
 
 // APIError represents an error returned by the GoCD API.
@@ -57,6 +83,15 @@ func IsNotFound(err error) bool {
 	return false
 }
 
+// IsForbidden returns true if the error is a 403 Forbidden.
+func IsForbidden(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
 // client is an http-based implementation of Client.
 type client struct {
 	http  *http.Client
@@ -66,6 +101,10 @@ type client struct {
 	basic bool
 	user  string
 	pass  string
+
+	versionMu    sync.Mutex
+	versionKnown bool
+	version      ServerRelease
 }
 
 // New creates a new GoCD API client.
@@ -79,11 +118,28 @@ func New(cfg Config) (Client, error) {
 		return nil, fmt.Errorf("gocd: invalid BaseURL: %w", err)
 	}
 
-	tr := &http.Transport{}
+	// Clients are long-lived and shared between reconciles, so keep enough idle
+	// connections around for concurrent reconciles to reuse them.
+	proxy, err := proxyFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tr := &http.Transport{
+		Proxy:               proxy,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
 	if u.Scheme == "https" {
 		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: cfg.Insecure} // #nosec G402: intentional, controlled by config
 	}
-	httpClient := &http.Client{Transport: tr}
+	var rt http.RoundTripper = tr
+	if cfg.WrapTransport != nil {
+		rt = cfg.WrapTransport(rt)
+	}
+	rt = newWireLogTransport(rt, cfg.Logger, cfg.MaxLogBodyBytes)
+	rt = &instrumentedTransport{next: rt}
+	httpClient := &http.Client{Transport: newLimitedTransport(rt, cfg.Name, cfg.RequestsPerSecond, cfg.Burst, cfg.MaxInFlight)}
 	if cfg.Timeout > 0 {
 		httpClient.Timeout = cfg.Timeout
 	} else {
@@ -107,8 +163,23 @@ func New(cfg Config) (Client, error) {
 	return c, nil
 }
 
+// CloseIdleConnections closes connections kept alive by the underlying
+// transport. It should be called when a client is discarded.
+func (c *client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
+
 // do builds and executes an HTTP request against the GoCD API.
-func (c *client) do(ctx context.Context, method, path, accept string, headers map[string]string, body any) (*http.Response, error) {
+func (c *client) do(ctx context.Context, method, path, accept string, headers map[string]string, body any) (resp *http.Response, err error) {
+	svc := serviceFor(path)
+	ctx, span := tracer().Start(ctx, "GoCD "+method+" "+svc, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", method),
+			attribute.String("url.path", path),
+			attribute.String("gocd.service", svc),
+		))
+	defer func() { endSpan(span, resp, err) }()
+
 	// Build full URL
 	rel := &url.URL{Path: strings.TrimSuffix(c.base.Path, "/") + "/" + strings.TrimPrefix(path, "/")}
 	u := *c.base
@@ -123,7 +194,7 @@ func (c *client) do(ctx context.Context, method, path, accept string, headers ma
 		rdr = io.NopCloser(strings.NewReader(string(b)))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), rdr)
+	req, err := http.NewRequestWithContext(withService(ctx, svc), method, u.String(), rdr)
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +216,11 @@ func (c *client) do(ctx context.Context, method, path, accept string, headers ma
 		req.Header.Set(k, v)
 	}
 
-	resp, err := c.http.Do(req)
+	if etag := req.Header.Get("If-Match"); etag != "" {
+		span.SetAttributes(attribute.String("gocd.if_match", etag))
+	}
+
+	resp, err = c.http.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -169,3 +244,24 @@ func decodeJSON(resp *http.Response, out any) error {
 	dec := json.NewDecoder(resp.Body)
 	return dec.Decode(out)
 }
+
+// overlayDocument returns the body to update the entity at path with: desired
+// overlaid onto the document GoCD has for it, read right before the update, so
+// attributes newer GoCD releases or plugins add are not wiped. GoCD rejects
+// the update if the entity changed since the ETag it is sent with, so the
+// document read is the one the ETag was observed with. Without a document the
+// body is desired alone.
+func (c *client) overlayDocument(ctx context.Context, path, service string, desired any) (json.RawMessage, error) {
+	resp, err := c.doVersioned(ctx, http.MethodGet, path, service, nil, nil)
+	if err != nil && !IsNotFound(err) {
+		return nil, errors.Wrap(err, "gocd: cannot read the document to update")
+	}
+	var raw []byte
+	if err == nil {
+		defer resp.Body.Close() //nolint:errcheck
+		if raw, err = io.ReadAll(resp.Body); err != nil {
+			return nil, errors.Wrap(err, "gocd: cannot read the document to update")
+		}
+	}
+	return overlay(raw, desired)
+}
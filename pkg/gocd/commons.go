@@ -1,13 +1,22 @@
 package gocd
 
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
 // ConfigProperty represents a key/value property.
 type ConfigProperty struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Key            string `json:"key"`
+	Value          string `json:"value"`
+	EncryptedValue string `json:"encrypted_value,omitempty"`
 }
 
 func (c ConfigProperty) Equal(o ConfigProperty) bool {
-	return c.Key == o.Key && c.Value == o.Value
+	return c.Key == o.Key && c.Value == o.Value && c.EncryptedValue == o.EncryptedValue
 }
 
 // HALLinks represents standard GoCD _links
@@ -20,3 +29,31 @@ type HALLinks struct {
 type HALLink struct {
 	Href string `json:"href"`
 }
+
+// halCollection is the envelope of GoCD collection responses, which embed the
+// items under a resource specific key, e.g. {"_embedded": {"roles": [...]}}.
+type halCollection struct {
+	Embedded map[string]json.RawMessage `json:"_embedded"`
+}
+
+// listEmbedded gets the collection at path and decodes the items embedded
+// under key.
+func listEmbedded[T any](ctx context.Context, c *client, path, service, key string) ([]T, error) {
+	resp, err := c.doVersioned(ctx, http.MethodGet, path, service, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var col halCollection
+	if err := decodeJSON(resp, &col); err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to decode response")
+	}
+	raw, ok := col.Embedded[key]
+	if !ok {
+		return nil, errors.Errorf("gocd: response has no embedded %q collection", key)
+	}
+	var items []T
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, errors.Wrapf(err, "gocd: failed to decode embedded %q collection", key)
+	}
+	return items, nil
+}
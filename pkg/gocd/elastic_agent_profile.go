@@ -15,9 +15,9 @@ const (
 )
 
 type ElasticAgentProfile struct {
-	ID               string `json:"id"`
-	ClusterProfileID string `json:"cluster_profile_id"`
-	Properties       []ConfigProperty
+	ID               string           `json:"id"`
+	ClusterProfileID string           `json:"cluster_profile_id"`
+	Properties       []ConfigProperty `json:"properties"`
 }
 
 type ElasticAgentProfileResponse struct {
@@ -26,6 +26,7 @@ type ElasticAgentProfileResponse struct {
 }
 
 type ElasticAgentProfileService interface {
+	List(ctx context.Context) ([]ElasticAgentProfileResponse, error)
 	Get(ctx context.Context, profileID string) (*ElasticAgentProfileResponse, string, error)
 	Create(ctx context.Context, eap ElasticAgentProfile) (*ElasticAgentProfileResponse, string, error)
 	Update(ctx context.Context, eap ElasticAgentProfile, eTag string) (*ElasticAgentProfileResponse, string, error)
@@ -40,9 +41,17 @@ type elasticAgentProfileService struct {
 	c *client
 }
 
+func (e *elasticAgentProfileService) List(ctx context.Context) ([]ElasticAgentProfileResponse, error) {
+	profiles, err := listEmbedded[ElasticAgentProfileResponse](ctx, e.c, elasticAgentProfileSerivcePath, ServiceElasticAgentProfiles, "profiles")
+	if err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to list the elastic agent profiles")
+	}
+	return profiles, nil
+}
+
 func (e *elasticAgentProfileService) Get(ctx context.Context, profileID string) (*ElasticAgentProfileResponse, string, error) {
 	path := fmt.Sprintf("%s/%s", elasticAgentProfileSerivcePath, url.PathEscape(profileID))
-	resp, err := e.c.do(ctx, http.MethodGet, path, acceptElasticAgentProfile, nil, nil)
+	resp, err := e.c.doVersioned(ctx, http.MethodGet, path, ServiceElasticAgentProfiles, nil, nil)
 	if err != nil {
 		if IsNotFound(err) {
 			return nil, "", nil
@@ -58,7 +67,7 @@ func (e *elasticAgentProfileService) Get(ctx context.Context, profileID string)
 }
 
 func (e *elasticAgentProfileService) Create(ctx context.Context, eap ElasticAgentProfile) (*ElasticAgentProfileResponse, string, error) {
-	resp, err := e.c.do(ctx, http.MethodPost, elasticAgentProfileSerivcePath, acceptElasticAgentProfile, nil, eap)
+	resp, err := e.c.doVersioned(ctx, http.MethodPost, elasticAgentProfileSerivcePath, ServiceElasticAgentProfiles, nil, eap)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "gocd: failed to create the elastic agent profile")
 	}
@@ -75,7 +84,11 @@ func (e *elasticAgentProfileService) Update(ctx context.Context, eap ElasticAgen
 	headers := map[string]string{
 		"If-Match": etag,
 	}
-	resp, err := e.c.do(ctx, http.MethodPut, path, acceptElasticAgentProfile, headers, eap)
+	body, err := e.c.overlayDocument(ctx, path, ServiceElasticAgentProfiles, eap)
+	if err != nil {
+		return nil, "", errors.Wrap(err, fmt.Sprintf("gocd: could not update the elastic agent profile of id %s", eap.ID))
+	}
+	resp, err := e.c.doVersioned(ctx, http.MethodPut, path, ServiceElasticAgentProfiles, headers, body)
 	if err != nil {
 		return nil, "", errors.Wrap(err, fmt.Sprintf("gocd: could not update the elastic agent profile of id %s", eap.ID))
 	}
@@ -91,7 +104,7 @@ func (e *elasticAgentProfileService) Update(ctx context.Context, eap ElasticAgen
 
 func (e *elasticAgentProfileService) Delete(ctx context.Context, profileID string) error {
 	path := fmt.Sprintf("%s/%s", elasticAgentProfileSerivcePath, url.PathEscape(profileID))
-	resp, err := e.c.do(ctx, http.MethodDelete, path, acceptElasticAgentProfile, nil, nil)
+	resp, err := e.c.doVersioned(ctx, http.MethodDelete, path, ServiceElasticAgentProfiles, nil, nil)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("cannot delete the elastic agent profie with id %s", profileID))
 	}
@@ -0,0 +1,49 @@
+package gocd
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	acceptEncryption      = "application/vnd.go.cd.v1+json"
+	encryptionServicePath = "/go/api/admin/encrypt"
+)
+
+// EncryptionService encrypts values with the cipher of the GoCD server.
+// Encrypted values may be sent as the encrypted_value of secure variables and
+// properties, so that the plain text values do not appear in request bodies.
+// See: https://api.gocd.org/current/#encryption
+//
+// GoCD encrypts with a random initialization vector, so encrypting the same
+// value twice returns different encrypted values.
+type EncryptionService interface {
+	Encrypt(ctx context.Context, value string) (string, error)
+}
+
+type encryptionRequest struct {
+	Value string `json:"value"`
+}
+
+type encryptionResponse struct {
+	EncryptedValue string    `json:"encrypted_value"`
+	Links          *HALLinks `json:"_links,omitempty"`
+}
+
+type encryptionService struct{ c *client }
+
+func (c *client) Encryption() EncryptionService { return &encryptionService{c: c} }
+
+func (s *encryptionService) Encrypt(ctx context.Context, value string) (string, error) {
+	resp, err := s.c.doVersioned(ctx, http.MethodPost, encryptionServicePath, ServiceEncryption, nil, encryptionRequest{Value: value})
+	if err != nil {
+		return "", errors.Wrap(err, "gocd: failed to encrypt value")
+	}
+	var out encryptionResponse
+	if err := decodeJSON(resp, &out); err != nil {
+		return "", errors.Wrap(err, "gocd: failed to decode response")
+	}
+	return out.EncryptedValue, nil
+}
@@ -0,0 +1,282 @@
+package gocd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces credentials in recorded fixtures.
+const Redacted = "REDACTED"
+
+// Headers kept in fixtures. Everything else, including Authorization and
+// cookies, is dropped when recording.
+var fixtureHeaders = []string{"Accept", "Content-Type", "If-Match", "ETag"}
+
+// sensitiveKey matches JSON keys and property keys whose values are scrubbed.
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passphrase|secret|token|private_?key|encrypted_)`)
+
+// IsSensitiveKey returns true if the values of a JSON key or plugin property
+// with the supplied name are likely to be credentials.
+func IsSensitiveKey(key string) bool {
+	return sensitiveKey.MatchString(key)
+}
+
+// A Cassette is a recorded sequence of GoCD API interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// An Interaction is a recorded request and the response it received.
+type Interaction struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest is a recorded request. The host is not recorded so fixtures
+// can be replayed against any base URL.
+type FixtureRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// FixtureResponse is a recorded response.
+type FixtureResponse struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       json.RawMessage   `json:"body,omitempty"`
+	// Text holds bodies that are not JSON.
+	Text string `json:"text,omitempty"`
+}
+
+// A Recorder records the interactions of a Client with scrubbed credentials.
+// Set Config.WrapTransport to its Wrap method and call Save when done.
+type Recorder struct {
+	path string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a Recorder that saves to the supplied file.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// Wrap returns a transport that records every interaction sent through next.
+func (r *Recorder) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		var reqBody []byte
+		if req.Body != nil {
+			b, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			_ = req.Body.Close()
+			reqBody = b
+			req.Body = io.NopCloser(bytes.NewReader(b))
+		}
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+		in := Interaction{
+			Request: FixtureRequest{
+				Method:  req.Method,
+				Path:    req.URL.Path,
+				Headers: fixtureHeaderMap(req.Header),
+				Body:    scrubJSON(reqBody),
+			},
+			Response: FixtureResponse{
+				StatusCode: resp.StatusCode,
+				Headers:    fixtureHeaderMap(resp.Header),
+			},
+		}
+		if body := scrubJSON(respBody); body != nil || len(respBody) == 0 {
+			in.Response.Body = body
+		} else {
+			in.Response.Text = string(respBody)
+		}
+
+		r.mu.Lock()
+		r.cassette.Interactions = append(r.cassette.Interactions, in)
+		r.mu.Unlock()
+		return resp, nil
+	})
+}
+
+// Save writes the recorded interactions to the Recorder's file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o600)
+}
+
+// A Replayer serves recorded interactions instead of sending requests to a
+// GoCD server. Interactions are matched by method and path in recording
+// order, so a fixture may hold several responses for the same endpoint.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer loads the interactions recorded in the supplied file.
+func NewReplayer(path string) (*Replayer, error) {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("gocd: cannot parse fixture %s: %w", path, err)
+	}
+	return &Replayer{interactions: c.Interactions, used: make([]bool, len(c.Interactions))}, nil
+}
+
+// Wrap returns the Replayer itself; the wrapped transport is never used.
+func (r *Replayer) Wrap(http.RoundTripper) http.RoundTripper {
+	return r
+}
+
+// RoundTrip serves the first unused interaction matching the request.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.Path != req.URL.Path {
+			continue
+		}
+		r.used[i] = true
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		body := []byte(in.Response.Body)
+		if in.Response.Text != "" {
+			body = []byte(in.Response.Text)
+		}
+		h := http.Header{}
+		for k, v := range in.Response.Headers {
+			h.Set(k, v)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        h,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("gocd: no recorded interaction left for %s %s", req.Method, req.URL.Path)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func fixtureHeaderMap(h http.Header) map[string]string {
+	out := map[string]string{}
+	for _, k := range fixtureHeaders {
+		if v := h.Get(k); v != "" {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// scrubJSON returns the indented body with credentials replaced by Redacted,
+// or nil if the body is empty or not JSON.
+func scrubJSON(b []byte) json.RawMessage {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	out, err := json.MarshalIndent(scrub(v), "", "  ")
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// CredentialFields returns the fields of an object decoded from GoCD JSON
+// whose values are credentials.
+func CredentialFields(m map[string]any) []string {
+	var out []string
+	// Plugin properties ({"key": "Password", "value": "..."}) and secure
+	// environment variables carry secrets in a generic "value" field.
+	if k, ok := m["key"].(string); ok && sensitiveKey.MatchString(k) {
+		out = append(out, "value", "encrypted_value")
+	}
+	if secure, _ := m["secure"].(bool); secure {
+		out = append(out, "value")
+	}
+	for k, v := range m {
+		if _, ok := v.(string); ok && sensitiveKey.MatchString(k) {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func scrub(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		credentials := CredentialFields(t)
+		for _, k := range credentials {
+			scrubValue(t, k)
+		}
+		for k, val := range t {
+			if !slices.Contains(credentials, k) {
+				t[k] = scrub(val)
+			}
+		}
+		return t
+	case []any:
+		for i := range t {
+			t[i] = scrub(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func scrubValue(m map[string]any, key string) {
+	if s, ok := m[key].(string); ok && strings.TrimSpace(s) != "" {
+		m[key] = Redacted
+	}
+}
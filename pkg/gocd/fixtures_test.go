@@ -0,0 +1,157 @@
+package gocd_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+// TestPipelineConfigFixtures replays every recorded pipeline config under
+// testdata/fixtures and checks that materials and tasks are fully decoded.
+//
+// To capture fixtures from a live server, run TestRecordPipelineConfigFixtures
+// with GOCD_RECORD_URL, GOCD_RECORD_PIPELINES (comma separated) and either
+// GOCD_RECORD_TOKEN or GOCD_RECORD_USERNAME/GOCD_RECORD_PASSWORD set.
+func TestPipelineConfigFixtures(t *testing.T) {
+	files, _ := filepath.Glob("testdata/fixtures/*/pipeline_configs.json")
+	if len(files) == 0 {
+		t.Fatal("no pipeline config fixtures found")
+	}
+	for _, f := range files {
+		t.Run(filepath.Base(filepath.Dir(f)), func(t *testing.T) {
+			rp, err := gocd.NewReplayer(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gc, _ := gocd.New(gocd.Config{BaseURL: "http://gocd.invalid", WrapTransport: rp.Wrap})
+
+			for _, name := range recordedPipelines(t, f) {
+				pc, etag, err := gc.PipelineConfigs().Get(context.Background(), name)
+				if err != nil {
+					t.Fatalf("PipelineConfigs.Get(%q) returned error: %v", name, err)
+				}
+				if pc == nil {
+					continue
+				}
+				if etag == "" {
+					t.Errorf("PipelineConfigs.Get(%q): no ETag", name)
+				}
+				for i, m := range pc.Materials {
+					if m.Attributes == nil {
+						t.Errorf("%s: material %d of type %q was not decoded", name, i, m.Type)
+					}
+				}
+				for _, s := range pc.Stages {
+					for _, j := range s.Jobs {
+						for i, task := range j.Tasks {
+							if task.Attributes == nil {
+								t.Errorf("%s: task %d of %s/%s of type %q was not decoded", name, i, s.Name, j.Name, task.Type)
+							}
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRecordPipelineConfigFixtures(t *testing.T) {
+	url := os.Getenv("GOCD_RECORD_URL")
+	if url == "" {
+		t.Skip("GOCD_RECORD_URL is not set")
+	}
+	cfg := gocd.Config{
+		BaseURL:  url,
+		Token:    os.Getenv("GOCD_RECORD_TOKEN"),
+		Username: os.Getenv("GOCD_RECORD_USERNAME"),
+		Password: os.Getenv("GOCD_RECORD_PASSWORD"),
+	}
+	live, _ := gocd.New(cfg)
+	v, err := live.Server().Version(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := gocd.NewRecorder(filepath.Join("testdata", "fixtures", v.Version, "pipeline_configs.json"))
+	cfg.WrapTransport = rec.Wrap
+	gc, _ := gocd.New(cfg)
+	for _, name := range strings.Split(os.Getenv("GOCD_RECORD_PIPELINES"), ",") {
+		if _, _, err := gc.PipelineConfigs().Get(context.Background(), strings.TrimSpace(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecorderScrubsCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"1"`)
+		w.Header().Set("Set-Cookie", "JSESSIONID=abc")
+		fmt.Fprintln(w, `{"id": "ldap", "plugin_id": "ldap", "properties": [{"key": "Password", "value": "hunter2"}, {"key": "Url", "value": "ldap://ldap"}]}`)
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	rec := gocd.NewRecorder(path)
+	gc, _ := gocd.New(gocd.Config{BaseURL: ts.URL, Username: "admin", Password: "topsecret", WrapTransport: rec.Wrap})
+	cfg := gocd.AuthorizationConfiguration{ID: "ldap", Properties: []gocd.ConfigProperty{{Key: "ManagerPassword", Value: "s3cr3t"}}}
+	if _, _, err := gc.AuthorizationConfigurations().Create(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := os.ReadFile(path)
+	for _, secret := range []string{"hunter2", "s3cr3t", "topsecret", "Authorization", "JSESSIONID"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("fixture contains %q:\n%s", secret, b)
+		}
+	}
+	if !strings.Contains(string(b), "ldap://ldap") {
+		t.Errorf("fixture lost non-sensitive values:\n%s", b)
+	}
+
+	rp, err := gocd.NewReplayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay, _ := gocd.New(gocd.Config{BaseURL: "http://gocd.invalid", WrapTransport: rp.Wrap})
+	got, etag, err := replay.AuthorizationConfigurations().Create(context.Background(), cfg)
+	if err != nil || got.ID != "ldap" || etag != `"1"` {
+		t.Errorf("replayed Create returned %+v, %q, %v", got, etag, err)
+	}
+	if _, _, err := replay.AuthorizationConfigurations().Create(context.Background(), cfg); err == nil {
+		t.Errorf("expected an error once the recorded interactions are exhausted")
+	}
+}
+
+// recordedPipelines returns the names of the pipelines requested in a fixture.
+func recordedPipelines(t *testing.T, path string) []string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c gocd.Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, in := range c.Interactions {
+		if name, ok := strings.CutPrefix(in.Request.Path, "/go/api/admin/pipelines/"); ok && in.Request.Method == http.MethodGet {
+			names = append(names, name)
+		}
+	}
+	return names
+}
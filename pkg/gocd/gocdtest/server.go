@@ -0,0 +1,558 @@
+// Package gocdtest provides an in-process fake GoCD server for tests and local
+// development. It keeps state in memory and implements the subset of the GoCD
+// API used by this provider with the same ETag/If-Match semantics, accept
+// header versioning and validation errors as a real server.
+package gocdtest
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+// Kind identifies a collection of entities served by the fake server.
+type Kind string
+
+// Kinds of entities served by the fake server.
+const (
+	Roles           Kind = "roles"
+	AuthConfigs     Kind = "auth_configs"
+	ElasticProfiles Kind = "elastic_profiles"
+	Pipelines       Kind = "pipelines"
+)
+
+// DefaultVersion is the GoCD version reported by the fake server.
+const DefaultVersion = "25.3.0"
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z0-9_\-][a-zA-Z0-9_\-.]*$`)
+
+// collection describes how an entity kind is exposed by the API.
+type collection struct {
+	kind     Kind
+	path     string
+	accept   string
+	since    string // First GoCD release serving accept.
+	embedded string // Key of the items in list responses; "" if the API cannot list.
+	idField  string
+	validate func(obj map[string]any) map[string][]string
+	objects  map[string]map[string]any
+}
+
+// A Server is a stateful fake GoCD server backed by httptest.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	collections map[Kind]*collection
+	version     string
+	username    string
+	password    string
+	admin       bool
+	requests    []Request
+	encryptions int
+}
+
+// A Request records a request received by the fake server.
+type Request struct {
+	Method string
+	Path   string
+	Accept string
+}
+
+// An Option configures a Server.
+type Option func(*Server)
+
+// WithVersion sets the GoCD version reported by the server.
+func WithVersion(v string) Option {
+	return func(s *Server) { s.version = v }
+}
+
+// WithBasicAuth requires every authenticated endpoint to be called with the
+// supplied credentials.
+func WithBasicAuth(username, password string) Option {
+	return func(s *Server) { s.username, s.password = username, password }
+}
+
+// WithAdmin sets whether the authenticated user is a system administrator.
+// Non-admins receive 403 Forbidden from the admin endpoints.
+func WithAdmin(admin bool) Option {
+	return func(s *Server) { s.admin = admin }
+}
+
+// NewServer starts a fake GoCD server. Callers must Close it.
+func NewServer(o ...Option) *Server {
+	s := &Server{
+		version: DefaultVersion,
+		admin:   true,
+		collections: map[Kind]*collection{
+			Roles: {
+				kind: Roles, path: "/go/api/admin/security/roles", accept: "application/vnd.go.cd.v3+json", since: "19.11.0", embedded: "roles",
+				idField: "name", validate: validateRole,
+			},
+			AuthConfigs: {
+				kind: AuthConfigs, path: "/go/api/admin/security/auth_configs", accept: "application/vnd.go.cd.v2+json", since: "19.6.0", embedded: "auth_configs",
+				idField: "id", validate: requireFields("plugin_id"),
+			},
+			ElasticProfiles: {
+				kind: ElasticProfiles, path: "/go/api/elastic/profiles", accept: "application/vnd.go.cd.v2+json", since: "19.3.0", embedded: "profiles",
+				idField: "id", validate: requireFields("cluster_profile_id"),
+			},
+			Pipelines: {
+				kind: Pipelines, path: "/go/api/admin/pipelines", accept: "application/vnd.go.cd.v11+json", since: "20.8.0",
+				idField: "name", validate: validatePipeline,
+			},
+		},
+	}
+	for _, fn := range o {
+		fn(s)
+	}
+	for _, c := range s.collections {
+		c.objects = map[string]map[string]any{}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Seed stores obj as an existing entity of the supplied kind, bypassing
+// validation. obj may be any value that marshals to a JSON object.
+func (s *Server) Seed(k Kind, obj any) error {
+	m, err := toMap(obj)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collections[k]
+	id, _ := m[c.idField].(string)
+	if id == "" {
+		return fmt.Errorf("gocdtest: %s has no %q", k, c.idField)
+	}
+	c.objects[id] = m
+	return nil
+}
+
+// Get returns the stored entity of the supplied kind and id.
+func (s *Server) Get(k Kind, id string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.collections[k].objects[id]
+	return obj, ok
+}
+
+// ETag returns the current ETag of the stored entity, or "" if it does not exist.
+func (s *Server) ETag(k Kind, id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.collections[k].objects[id]
+	if !ok {
+		return ""
+	}
+	return etagOf(obj)
+}
+
+// IDs returns the sorted ids of the stored entities of the supplied kind.
+func (s *Server) IDs(k Kind) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedIDs(s.collections[k])
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Accept: r.Header.Get("Accept")})
+
+	switch r.URL.Path {
+	case "/go/api/version":
+		s.handleVersion(w, r)
+		return
+	case "/go/api/current_user":
+		if s.authenticate(w, r) {
+			writeJSON(w, http.StatusOK, map[string]any{"login_name": s.login(), "display_name": s.login(), "enabled": true})
+		}
+		return
+	case "/go/api/admin/security/system_admins":
+		if s.authenticate(w, r) && s.authorize(w) {
+			writeJSON(w, http.StatusOK, map[string]any{"roles": []string{}, "users": []string{s.login()}})
+		}
+		return
+	case "/go/api/admin/encrypt":
+		if s.authenticate(w, r) && s.authorize(w) {
+			s.handleEncrypt(w, r)
+		}
+		return
+	case "/go/api/admin/pipeline_groups":
+		if s.authenticate(w, r) && s.authorize(w) {
+			s.listPipelineGroups(w, r)
+		}
+		return
+	}
+
+	for _, c := range s.collections {
+		if r.URL.Path != c.path && !strings.HasPrefix(r.URL.Path, c.path+"/") {
+			continue
+		}
+		if !s.authenticate(w, r) || !s.authorize(w) {
+			return
+		}
+		if !s.serves(c) {
+			writeMessage(w, http.StatusNotFound, "The url you are trying to reach appears to have an error.")
+			return
+		}
+		if r.Header.Get("Accept") != c.accept {
+			// A real server answers 404 to an unsupported version, which is
+			// indistinguishable from a missing entity. Fail loudly instead.
+			writeMessage(w, http.StatusNotAcceptable, fmt.Sprintf("Unsupported Accept header %q, expected %q", r.Header.Get("Accept"), c.accept))
+			return
+		}
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, c.path), "/")
+		switch {
+		case id == "" && r.Method == http.MethodPost:
+			s.create(w, r, c)
+		case id == "" && r.Method == http.MethodGet && c.embedded != "":
+			s.list(w, c)
+		case id != "" && r.Method == http.MethodGet:
+			s.get(w, c, id)
+		case id != "" && r.Method == http.MethodPut:
+			s.update(w, r, c, id)
+		case id != "" && r.Method == http.MethodDelete:
+			s.delete(w, r, c, id)
+		default:
+			writeMessage(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+		return
+	}
+	writeMessage(w, http.StatusNotFound, "The resource you requested was not found!")
+}
+
+// handleEncrypt encrypts a value like GoCD's encryption API.
+func (s *Server) handleEncrypt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeMessage(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if r.Header.Get("Accept") != "application/vnd.go.cd.v1+json" {
+		writeMessage(w, http.StatusNotAcceptable, fmt.Sprintf("Unsupported Accept header %q", r.Header.Get("Accept")))
+		return
+	}
+	obj, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	v, ok := obj["value"].(string)
+	if !ok {
+		writeMessage(w, http.StatusUnprocessableEntity, "Value is required")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"encrypted_value": s.encrypt(v)})
+}
+
+// encrypt returns an encrypted value in the format of GoCD's AES cipher. Like
+// GoCD it uses a new initialization vector every time, so encrypting a value
+// twice returns different encrypted values.
+func (s *Server) encrypt(v string) string {
+	s.encryptions++
+	return fmt.Sprintf("AES:%08x:%s", s.encryptions, base64.StdEncoding.EncodeToString([]byte(v)))
+}
+
+// encryptVariables stores the values of the secure variables of a pipeline
+// encrypted, as GoCD does. Encrypted values sent by the client are kept.
+func (s *Server) encryptVariables(pipeline map[string]any) {
+	each := func(obj map[string]any) {
+		vars, _ := obj["environment_variables"].([]any)
+		for _, v := range vars {
+			m, _ := v.(map[string]any)
+			if secure, _ := m["secure"].(bool); !secure {
+				continue
+			}
+			if value, _ := m["value"].(string); value != "" {
+				m["encrypted_value"] = s.encrypt(value)
+			}
+			delete(m, "value")
+		}
+	}
+	each(pipeline)
+	stages, _ := pipeline["stages"].([]any)
+	for _, st := range stages {
+		stage, _ := st.(map[string]any)
+		if stage == nil {
+			continue
+		}
+		each(stage)
+		jobs, _ := stage["jobs"].([]any)
+		for _, j := range jobs {
+			if job, _ := j.(map[string]any); job != nil {
+				each(job)
+			}
+		}
+	}
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"version":      s.version,
+		"build_number": "1",
+		"git_sha":      "0000000",
+		"full_version": s.version + " (1-0000000)",
+		"commit_url":   "https://github.com/gocd/gocd/commits/0000000",
+	})
+}
+
+// serves returns true if the reported version is recent enough to serve the
+// media type of the collection. Unparseable versions serve everything.
+func (s *Server) serves(c *collection) bool {
+	v, err := gocd.ParseServerRelease(s.version)
+	if err != nil {
+		return true
+	}
+	since, _ := gocd.ParseServerRelease(c.since)
+	return !v.Less(since)
+}
+
+func (s *Server) login() string {
+	if s.username == "" {
+		return "anonymous"
+	}
+	return s.username
+}
+
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) bool {
+	if s.username == "" {
+		return true
+	}
+	if u, p, ok := r.BasicAuth(); ok && u == s.username && p == s.password {
+		return true
+	}
+	writeMessage(w, http.StatusUnauthorized, "You are not authenticated!")
+	return false
+}
+
+func (s *Server) authorize(w http.ResponseWriter) bool {
+	if s.admin {
+		return true
+	}
+	writeMessage(w, http.StatusForbidden, "You are not authorized to perform this action.")
+	return false
+}
+
+func (s *Server) get(w http.ResponseWriter, c *collection, id string) {
+	obj, ok := c.objects[id]
+	if !ok {
+		writeNotFound(w, c, id)
+		return
+	}
+	writeEntity(w, http.StatusOK, c, obj)
+}
+
+func (s *Server) list(w http.ResponseWriter, c *collection) {
+	items := make([]map[string]any, 0, len(c.objects))
+	for _, id := range sortedIDs(c) {
+		items = append(items, withLinks(c, c.objects[id]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"_links":    map[string]any{"self": map[string]any{"href": "http://gocd.test" + c.path}},
+		"_embedded": map[string]any{c.embedded: items},
+	})
+}
+
+// listPipelineGroups serves the pipeline groups derived from the group of the
+// stored pipelines.
+func (s *Server) listPipelineGroups(w http.ResponseWriter, r *http.Request) {
+	if accept := "application/vnd.go.cd.v1+json"; r.Header.Get("Accept") != accept {
+		writeMessage(w, http.StatusNotAcceptable, fmt.Sprintf("Unsupported Accept header %q, expected %q", r.Header.Get("Accept"), accept))
+		return
+	}
+	c := s.collections[Pipelines]
+	byGroup := map[string][]map[string]any{}
+	for _, id := range sortedIDs(c) {
+		g, _ := c.objects[id]["group"].(string)
+		byGroup[g] = append(byGroup[g], map[string]any{
+			"name":   id,
+			"_links": map[string]any{"self": map[string]any{"href": "http://gocd.test" + c.path + "/" + id}},
+		})
+	}
+	names := make([]string, 0, len(byGroup))
+	for g := range byGroup {
+		names = append(names, g)
+	}
+	sort.Strings(names)
+	groups := make([]map[string]any, 0, len(names))
+	for _, g := range names {
+		groups = append(groups, map[string]any{"name": g, "pipelines": byGroup[g]})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"_embedded": map[string]any{"groups": groups}})
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, c *collection) {
+	obj, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if c.kind == Pipelines {
+		// Pipelines are created with a {"group": ..., "pipeline": {...}} envelope.
+		p, _ := obj["pipeline"].(map[string]any)
+		if p == nil {
+			writeMessage(w, http.StatusUnprocessableEntity, "Json `{\"group\":...,\"pipeline\":...}` is required")
+			return
+		}
+		p["group"] = obj["group"]
+		obj = p
+	}
+	id, _ := obj[c.idField].(string)
+	if _, exists := c.objects[id]; exists && id != "" {
+		writeMessage(w, http.StatusUnprocessableEntity, fmt.Sprintf("Failed to add %s '%s'. Another %s with the same name already exists.", c.kind, id, c.kind))
+		return
+	}
+	if errs := c.validate(obj); len(errs) > 0 {
+		writeValidation(w, c, obj, errs)
+		return
+	}
+	if c.kind == Pipelines {
+		s.encryptVariables(obj)
+	}
+	c.objects[id] = obj
+	writeEntity(w, http.StatusOK, c, obj)
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, c *collection, id string) {
+	cur, ok := c.objects[id]
+	if !ok {
+		writeNotFound(w, c, id)
+		return
+	}
+	if r.Header.Get("If-Match") != etagOf(cur) {
+		writeMessage(w, http.StatusPreconditionFailed, fmt.Sprintf("Someone has modified the configuration for %s '%s'. Please update your copy of the config with the changes and try again.", c.kind, id))
+		return
+	}
+	obj, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	if got, _ := obj[c.idField].(string); got != id {
+		writeMessage(w, http.StatusUnprocessableEntity, fmt.Sprintf("Renaming of %s is not supported by this API.", c.kind))
+		return
+	}
+	if errs := c.validate(obj); len(errs) > 0 {
+		writeValidation(w, c, obj, errs)
+		return
+	}
+	if c.kind == Pipelines {
+		s.encryptVariables(obj)
+	}
+	c.objects[id] = obj
+	writeEntity(w, http.StatusOK, c, obj)
+}
+
+func (s *Server) delete(w http.ResponseWriter, r *http.Request, c *collection, id string) {
+	cur, ok := c.objects[id]
+	if !ok {
+		writeNotFound(w, c, id)
+		return
+	}
+	if m := r.Header.Get("If-Match"); m != "" && m != etagOf(cur) {
+		writeMessage(w, http.StatusPreconditionFailed, fmt.Sprintf("Someone has modified the configuration for %s '%s'.", c.kind, id))
+		return
+	}
+	delete(c.objects, id)
+	writeMessage(w, http.StatusOK, fmt.Sprintf("The %s '%s' was deleted successfully.", c.kind, id))
+}
+
+func readObject(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	var obj map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&obj); err != nil || obj == nil {
+		writeMessage(w, http.StatusBadRequest, "Error parsing request body")
+		return nil, false
+	}
+	// Links are generated by the server and ignored when sent back.
+	delete(obj, "_links")
+	return obj, true
+}
+
+func writeEntity(w http.ResponseWriter, status int, c *collection, obj map[string]any) {
+	w.Header().Set("ETag", etagOf(obj))
+	writeJSON(w, status, withLinks(c, obj))
+}
+
+// withLinks returns a copy of obj with the links the server generates.
+func withLinks(c *collection, obj map[string]any) map[string]any {
+	out := make(map[string]any, len(obj)+1)
+	for k, v := range obj {
+		out[k] = v
+	}
+	id, _ := obj[c.idField].(string)
+	out["_links"] = map[string]any{
+		"self": map[string]any{"href": "http://gocd.test" + c.path + "/" + id},
+		"doc":  map[string]any{"href": "https://api.gocd.org/current/"},
+		"find": map[string]any{"href": "http://gocd.test" + c.path + "/:" + c.idField},
+	}
+	return out
+}
+
+func writeNotFound(w http.ResponseWriter, c *collection, id string) {
+	writeMessage(w, http.StatusNotFound, fmt.Sprintf("Either the resource you requested was not found, or you are not authorized to perform this action. %s '%s' does not exist.", c.kind, id))
+}
+
+func writeValidation(w http.ResponseWriter, c *collection, obj map[string]any, errs map[string][]string) {
+	data := make(map[string]any, len(obj)+1)
+	for k, v := range obj {
+		data[k] = v
+	}
+	data["errors"] = errs
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+		"message": fmt.Sprintf("Validations failed for %s. Please correct and resubmit.", c.kind),
+		"data":    data,
+	})
+}
+
+func writeMessage(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]any{"message": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// etagOf returns a strong ETag derived from the entity's content so that any
+// change produces a new ETag, like GoCD does.
+func etagOf(obj map[string]any) string {
+	b, _ := json.Marshal(obj)
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func sortedIDs(c *collection) []string {
+	ids := make([]string, 0, len(c.objects))
+	for id := range c.objects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func toMap(obj any) (map[string]any, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	return m, json.Unmarshal(b, &m)
+}
@@ -0,0 +1,244 @@
+package gocdtest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/gocd/gocdtest"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
+)
+
+func TestRolesLifecycle(t *testing.T) {
+	srv := gocdtest.NewServer(gocdtest.WithBasicAuth("admin", "secret"))
+	defer srv.Close()
+	gc, _ := gocd.New(gocd.Config{BaseURL: srv.URL, Username: "admin", Password: "secret"})
+	ctx := context.Background()
+
+	role := gocd.Role{Name: "devs", Type: "gocd", Attributes: &gocd.RoleAttributes{Users: []string{"alice"}}}
+	_, etag, err := gc.Roles().Create(ctx, role)
+	if err != nil {
+		t.Fatalf("Roles.Create returned error: %v", err)
+	}
+	if etag == "" || etag != srv.ETag(gocdtest.Roles, "devs") {
+		t.Errorf("Roles.Create returned ETag %q, server has %q", etag, srv.ETag(gocdtest.Roles, "devs"))
+	}
+
+	if _, _, err := gc.Roles().Create(ctx, role); !isStatus(err, http.StatusUnprocessableEntity) {
+		t.Errorf("Roles.Create of an existing role: want 422, got %v", err)
+	}
+
+	got, getETag, err := gc.Roles().Get(ctx, "devs")
+	if err != nil || got == nil {
+		t.Fatalf("Roles.Get returned %v, %v", got, err)
+	}
+	if getETag != etag {
+		t.Errorf("Roles.Get returned ETag %q, want %q", getETag, etag)
+	}
+
+	role.Attributes.Users = []string{"alice", "bob"}
+	_, newETag, err := gc.Roles().Update(ctx, "devs", role, etag)
+	if err != nil {
+		t.Fatalf("Roles.Update returned error: %v", err)
+	}
+	if newETag == etag {
+		t.Errorf("Roles.Update did not change the ETag")
+	}
+
+	if _, _, err := gc.Roles().Update(ctx, "devs", role, etag); !isStatus(err, http.StatusPreconditionFailed) {
+		t.Errorf("Roles.Update with a stale ETag: want 412, got %v", err)
+	}
+
+	if err := gc.Roles().Delete(ctx, "devs", newETag); err != nil {
+		t.Fatalf("Roles.Delete returned error: %v", err)
+	}
+	if got, _, err := gc.Roles().Get(ctx, "devs"); got != nil || err != nil {
+		t.Errorf("Roles.Get after delete returned %v, %v", got, err)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	gc, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	ctx := context.Background()
+
+	if _, _, err := gc.Roles().Create(ctx, gocd.Role{Name: ".invalid", Type: "gocd"}); !isStatus(err, http.StatusUnprocessableEntity) {
+		t.Errorf("Roles.Create with an invalid name: want 422, got %v", err)
+	}
+	if _, _, err := gc.AuthorizationConfigurations().Create(ctx, gocd.AuthorizationConfiguration{ID: "ldap"}); !isStatus(err, http.StatusUnprocessableEntity) {
+		t.Errorf("AuthorizationConfigurations.Create without a plugin: want 422, got %v", err)
+	}
+	if _, _, err := gc.PipelineConfigs().Create(ctx, &gocd.PipelineConfig{Name: ptr.ToPtr("build"), Group: ptr.ToPtr("sample")}); !isStatus(err, http.StatusUnprocessableEntity) {
+		t.Errorf("PipelineConfigs.Create without materials and stages: want 422, got %v", err)
+	}
+	if ids := srv.IDs(gocdtest.Pipelines); len(ids) != 0 {
+		t.Errorf("Invalid pipeline was stored: %v", ids)
+	}
+}
+
+func TestPipelineConfigsCreate(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	gc, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+
+	pc := &gocd.PipelineConfig{
+		Name:      ptr.ToPtr("build"),
+		Group:     ptr.ToPtr("sample"),
+		Materials: []gocd.PipelineConfigMaterial{{Type: gocd.PipelineConfigMaterialTypeGit, Attributes: &gocd.PipelineConfigMaterialAttributesGit{URL: ptr.ToPtr("https://example.com/repo.git")}}},
+		Stages:    []gocd.PipelineConfigStage{{Name: "test"}},
+	}
+	if _, _, err := gc.PipelineConfigs().Create(context.Background(), pc); err != nil {
+		t.Fatalf("PipelineConfigs.Create returned error: %v", err)
+	}
+	got, ok := srv.Get(gocdtest.Pipelines, "build")
+	if !ok || got["group"] != "sample" {
+		t.Errorf("PipelineConfigs.Create stored %v", got)
+	}
+}
+
+func TestEncryption(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	gc, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	ctx := context.Background()
+
+	first, err := gc.Encryption().Encrypt(ctx, "hunter2")
+	if err != nil {
+		t.Fatalf("Encryption.Encrypt returned error: %v", err)
+	}
+	second, _ := gc.Encryption().Encrypt(ctx, "hunter2")
+	if !strings.HasPrefix(first, "AES:") || first == second {
+		t.Errorf("Encryption.Encrypt: want distinct AES encrypted values, got %q and %q", first, second)
+	}
+
+	pc := &gocd.PipelineConfig{
+		Name:  ptr.ToPtr("build"),
+		Group: ptr.ToPtr("sample"),
+		EnvironmentVariables: []gocd.EnvironmentVariable{
+			{Name: "PLAIN", Value: "visible"},
+			{Name: "TOKEN", Value: "hunter2", Secure: true},
+			{Name: "PASSWORD", EncryptedValue: first, Secure: true},
+		},
+		Materials: []gocd.PipelineConfigMaterial{{Type: gocd.PipelineConfigMaterialTypeGit, Attributes: &gocd.PipelineConfigMaterialAttributesGit{URL: ptr.ToPtr("https://example.com/repo.git")}}},
+		Stages:    []gocd.PipelineConfigStage{{Name: "test"}},
+	}
+	if _, _, err := gc.PipelineConfigs().Create(ctx, pc); err != nil {
+		t.Fatalf("PipelineConfigs.Create returned error: %v", err)
+	}
+	got, _, err := gc.PipelineConfigs().Get(ctx, "build")
+	if err != nil {
+		t.Fatalf("PipelineConfigs.Get returned error: %v", err)
+	}
+	vars := got.EnvironmentVariables
+	if vars[0].Value != "visible" {
+		t.Errorf("PipelineConfigs.Get: want plain value kept, got %+v", vars[0])
+	}
+	if vars[1].Value != "" || !strings.HasPrefix(vars[1].EncryptedValue, "AES:") {
+		t.Errorf("PipelineConfigs.Get: want secure value encrypted, got %+v", vars[1])
+	}
+	if vars[2].EncryptedValue != first {
+		t.Errorf("PipelineConfigs.Get: want encrypted value %q kept, got %+v", first, vars[2])
+	}
+}
+
+func TestAcceptHeaderVersion(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/go/api/admin/security/roles/devs", nil)
+	req.Header.Set("Accept", "application/vnd.go.cd.v1+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusNotAcceptable {
+		t.Errorf("Unsupported accept header: want 406, got %d", resp.StatusCode)
+	}
+}
+
+func TestServerEndpoints(t *testing.T) {
+	srv := gocdtest.NewServer(gocdtest.WithBasicAuth("bot", "secret"), gocdtest.WithAdmin(false), gocdtest.WithVersion("24.1.0"))
+	defer srv.Close()
+	gc, _ := gocd.New(gocd.Config{BaseURL: srv.URL, Username: "bot", Password: "secret"})
+	ctx := context.Background()
+
+	v, err := gc.Server().Version(ctx)
+	if err != nil || v.Version != "24.1.0" {
+		t.Errorf("Server.Version returned %v, %v", v, err)
+	}
+	u, err := gc.Server().CurrentUser(ctx)
+	if err != nil || u.LoginName != "bot" {
+		t.Errorf("Server.CurrentUser returned %v, %v", u, err)
+	}
+	admin, err := gc.Server().IsAdmin(ctx)
+	if err != nil || admin {
+		t.Errorf("Server.IsAdmin returned %v, %v", admin, err)
+	}
+
+	wrong, _ := gocd.New(gocd.Config{BaseURL: srv.URL, Username: "bot", Password: "wrong"})
+	if _, err := wrong.Server().CurrentUser(ctx); !isStatus(err, http.StatusUnauthorized) {
+		t.Errorf("Server.CurrentUser with wrong credentials: want 401, got %v", err)
+	}
+}
+
+func isStatus(err error, status int) bool {
+	var apiErr *gocd.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+func TestList(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	gc, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	ctx := context.Background()
+
+	seed := map[gocdtest.Kind][]any{
+		gocdtest.Roles:           {gocd.Role{Name: "ops", Type: "gocd"}, gocd.Role{Name: "devs", Type: "gocd"}},
+		gocdtest.AuthConfigs:     {gocd.AuthorizationConfiguration{ID: "ldap", PluginID: "cd.go.authentication.ldap"}},
+		gocdtest.ElasticProfiles: {gocd.ElasticAgentProfile{ID: "k8s", ClusterProfileID: "cluster"}},
+		gocdtest.Pipelines: {
+			map[string]any{"name": "build", "group": "main"},
+			map[string]any{"name": "deploy", "group": "main"},
+			map[string]any{"name": "tools", "group": "infra"},
+		},
+	}
+	for k, objs := range seed {
+		for _, o := range objs {
+			if err := srv.Seed(k, o); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	roles, err := gc.Roles().List(ctx)
+	if err != nil || len(roles) != 2 || roles[0].Name != "devs" || roles[1].Name != "ops" {
+		t.Errorf("Roles.List returned %+v, %v", roles, err)
+	}
+	if len(roles) > 0 && (roles[0].Links == nil || roles[0].Links.Self == nil) {
+		t.Errorf("Roles.List: want items with links, got %+v", roles[0])
+	}
+	authz, err := gc.AuthorizationConfigurations().List(ctx)
+	if err != nil || len(authz) != 1 || authz[0].ID != "ldap" {
+		t.Errorf("AuthorizationConfigurations.List returned %+v, %v", authz, err)
+	}
+	profiles, err := gc.ElasticAgentProfile().List(ctx)
+	if err != nil || len(profiles) != 1 || profiles[0].ID != "k8s" || profiles[0].ClusterProfileID != "cluster" {
+		t.Errorf("ElasticAgentProfile.List returned %+v, %v", profiles, err)
+	}
+	pipelines, err := gc.PipelineConfigs().List(ctx)
+	want := []gocd.PipelineReference{{Name: "tools", Group: "infra"}, {Name: "build", Group: "main"}, {Name: "deploy", Group: "main"}}
+	if err != nil || len(pipelines) != len(want) {
+		t.Fatalf("PipelineConfigs.List returned %+v, %v", pipelines, err)
+	}
+	for i := range want {
+		if pipelines[i].Name != want[i].Name || pipelines[i].Group != want[i].Group {
+			t.Errorf("PipelineConfigs.List[%d]: want %s/%s, got %s/%s", i, want[i].Group, want[i].Name, pipelines[i].Group, pipelines[i].Name)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package gocdtest
+
+func requireFields(fields ...string) func(map[string]any) map[string][]string {
+	return func(obj map[string]any) map[string][]string {
+		errs := map[string][]string{}
+		validateID(obj, "id", errs)
+		for _, f := range fields {
+			if s, _ := obj[f].(string); s == "" {
+				errs[f] = append(errs[f], f+" cannot be blank")
+			}
+		}
+		return errs
+	}
+}
+
+func validateID(obj map[string]any, field string, errs map[string][]string) {
+	id, _ := obj[field].(string)
+	if !namePattern.MatchString(id) {
+		errs[field] = append(errs[field], "Invalid "+field+" '"+id+"'. This must be alphanumeric and can contain underscores, hyphens and periods (however, it cannot start with a period).")
+	}
+}
+
+func validateRole(obj map[string]any) map[string][]string {
+	errs := map[string][]string{}
+	validateID(obj, "name", errs)
+	attrs, _ := obj["attributes"].(map[string]any)
+	switch obj["type"] {
+	case "gocd":
+	case "plugin":
+		if s, _ := attrs["auth_config_id"].(string); s == "" {
+			errs["auth_config_id"] = append(errs["auth_config_id"], "auth_config_id cannot be blank")
+		}
+	default:
+		errs["type"] = append(errs["type"], "Invalid role type. It must be one of 'gocd' or 'plugin'")
+	}
+	return errs
+}
+
+func validatePipeline(obj map[string]any) map[string][]string {
+	errs := map[string][]string{}
+	validateID(obj, "name", errs)
+	if s, _ := obj["group"].(string); s == "" {
+		errs["group"] = append(errs["group"], "Pipeline group must not be blank")
+	}
+	if m, _ := obj["materials"].([]any); len(m) == 0 {
+		errs["materials"] = append(errs["materials"], "A pipeline must have at least one material")
+	}
+	template, _ := obj["template"].(string)
+	stages, _ := obj["stages"].([]any)
+	switch {
+	case template == "" && len(stages) == 0:
+		errs["stages"] = append(errs["stages"], "A pipeline must have at least one stage")
+	case template != "" && len(stages) > 0:
+		errs["template"] = append(errs["template"], "Cannot add stages to a pipeline that is based on a template")
+	}
+	return errs
+}
@@ -0,0 +1,132 @@
+package gocd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "provider_gocd"
+
+var (
+	rateLimitWaitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "api",
+		Name:      "rate_limit_wait_seconds",
+		Help:      "Time requests spent waiting for the client-side rate limiter and concurrency cap of a ProviderConfig.",
+		Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider_config"})
+
+	apiRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "api",
+		Name:      "requests_total",
+		Help:      "Number of requests sent to the GoCD API.",
+	}, []string{"service", "method", "status_class"})
+
+	apiRequestDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to the GoCD API, excluding time spent waiting for client-side rate limiting.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method", "status_class"})
+
+	apiRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "api",
+		Name:      "requests_in_flight",
+		Help:      "Number of requests sent to the GoCD API that have not been answered yet.",
+	}, []string{"service"})
+)
+
+// servicePaths maps API path prefixes to the service label of their metrics.
+var servicePaths = []struct {
+	prefix  string
+	service string
+}{
+	{prefix: roleServicePath, service: ServiceRoles},
+	{prefix: servicePath, service: ServiceAuthorizationConfigurations},
+	{prefix: elasticAgentProfileSerivcePath, service: ServiceElasticAgentProfiles},
+	{prefix: pipelineConfigsServicePath, service: ServicePipelineConfigs},
+	{prefix: pipelineGroupsServicePath, service: ServicePipelineGroups},
+	{prefix: encryptionServicePath, service: ServiceEncryption},
+	{prefix: versionServicePath, service: "server"},
+	{prefix: currentUserServicePath, service: "server"},
+	{prefix: systemAdminsServicePath, service: "server"},
+}
+
+// Collectors returns the Prometheus collectors of the GoCD client so they can
+// be registered with the provider's metrics registry.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		rateLimitWaitSeconds,
+		apiRequestsTotal,
+		apiRequestDurationSeconds,
+		apiRequestsInFlight,
+	}
+}
+
+func serviceFor(path string) string {
+	for _, sp := range servicePaths {
+		if path == sp.prefix || strings.HasPrefix(path, sp.prefix+"/") {
+			return sp.service
+		}
+	}
+	return "other"
+}
+
+// statusClass returns the class of a response status, e.g. "2xx", or "error"
+// if no response was received.
+func statusClass(resp *http.Response, err error) string {
+	if err != nil || resp == nil {
+		return "error"
+	}
+	return strconv.Itoa(resp.StatusCode/100) + "xx"
+}
+
+func observeRequest(service, method string, resp *http.Response, err error, d time.Duration) {
+	class := statusClass(resp, err)
+	apiRequestsTotal.WithLabelValues(service, method, class).Inc()
+	apiRequestDurationSeconds.WithLabelValues(service, method, class).Observe(d.Seconds())
+}
+
+type serviceKey struct{}
+
+// withService returns a context labelling the metrics of the requests made
+// with it by service.
+func withService(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+
+// instrumentedTransport records the metrics of the requests sent through it.
+// It sits below the limitedTransport, so requests waiting for the limiter are
+// neither in flight nor timed.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	svc, ok := req.Context().Value(serviceKey{}).(string)
+	if !ok {
+		svc = "other"
+	}
+	apiRequestsInFlight.WithLabelValues(svc).Inc()
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	apiRequestsInFlight.WithLabelValues(svc).Dec()
+	observeRequest(svc, req.Method, resp, err, time.Since(start))
+	return resp, err
+}
+
+// CloseIdleConnections forwards to the wrapped transport so http.Client can
+// still release pooled connections.
+func (t *instrumentedTransport) CloseIdleConnections() {
+	if ic, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		ic.CloseIdleConnections()
+	}
+}
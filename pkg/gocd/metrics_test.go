@@ -0,0 +1,107 @@
+package gocd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestServiceFor(t *testing.T) {
+	cases := map[string]string{
+		"/go/api/admin/security/roles/admins":         "roles",
+		"/go/api/admin/security/auth_configs":         "authorization_configurations",
+		"/go/api/elastic/profiles/k8s":                "elastic_agent_profiles",
+		"/go/api/admin/pipelines/build":               "pipeline_configs",
+		"/go/api/admin/security/system_admins":        "server",
+		"/go/api/version":                             "server",
+		"/go/api/admin/pipelines_something_else/test": "other",
+	}
+	for path, want := range cases {
+		if got := serviceFor(path); got != want {
+			t.Errorf("serviceFor(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDoRecordsMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/go/api/admin/security/roles/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	ok := apiRequestsTotal.WithLabelValues("roles", http.MethodGet, "2xx")
+	notFound := apiRequestsTotal.WithLabelValues("roles", http.MethodGet, "4xx")
+	okBefore, notFoundBefore := testutil.ToFloat64(ok), testutil.ToFloat64(notFound)
+
+	gc, _ := New(Config{BaseURL: ts.URL})
+	_, _, _ = gc.Roles().Get(context.Background(), "admins")
+	_, _, _ = gc.Roles().Get(context.Background(), "missing")
+
+	if got := testutil.ToFloat64(ok) - okBefore; got != 1 {
+		t.Errorf("Expected 1 successful roles request, got %v", got)
+	}
+	if got := testutil.ToFloat64(notFound) - notFoundBefore; got != 1 {
+		t.Errorf("Expected 1 failed roles request, got %v", got)
+	}
+	if got := testutil.ToFloat64(apiRequestsInFlight.WithLabelValues("roles")); got != 0 {
+		t.Errorf("Expected no roles requests in flight, got %v", got)
+	}
+}
+
+func TestRateLimitWaitByProviderConfig(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"version": "25.3.0"}`))
+	}))
+	defer ts.Close()
+
+	gc, _ := New(Config{Name: "production", BaseURL: ts.URL, MaxInFlight: 1})
+	if _, err := gc.Server().Version(context.Background()); err != nil {
+		t.Fatalf("Server.Version returned error: %v", err)
+	}
+
+	// Delete reports whether the series was recorded.
+	if !rateLimitWaitSeconds.Delete(prometheus.Labels{"provider_config": "production"}) {
+		t.Errorf("Expected the wait to be recorded for ProviderConfig production")
+	}
+}
+
+func TestInFlightExcludesRateLimitWait(t *testing.T) {
+	entered, release := make(chan struct{}, 2), make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+		_, _ = w.Write([]byte(`{"version": "25.3.0"}`))
+	}))
+	defer ts.Close()
+
+	inFlight := apiRequestsInFlight.WithLabelValues("server")
+	before := testutil.ToFloat64(inFlight)
+
+	gc, _ := New(Config{BaseURL: ts.URL, MaxInFlight: 1})
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = gc.Server().Version(context.Background())
+		}()
+	}
+	<-entered
+	// Give the second request time to queue behind the concurrency cap.
+	time.Sleep(50 * time.Millisecond)
+	if got := testutil.ToFloat64(inFlight) - before; got != 1 {
+		t.Errorf("Expected 1 request in flight while the other waits for the limiter, got %v", got)
+	}
+	close(release)
+	wg.Wait()
+}
@@ -0,0 +1,72 @@
+package gocd
+
+import (
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	// A service whose newest media type is no longer served by newer releases.
+	compatibility["retired"] = []mediaTypeRange{{mediaType: "application/vnd.go.cd.v2+json", since: ServerRelease{19, 1, 0}, until: ServerRelease{23, 1, 0}}}
+	defer delete(compatibility, "retired")
+
+	cases := map[string]struct {
+		reason  string
+		service string
+		server  ServerRelease
+		want    string
+		wantErr string
+	}{
+		"Newest": {
+			reason:  "A current release should get the newest media type.",
+			service: ServiceRoles,
+			server:  ServerRelease{25, 3, 0},
+			want:    acceptRoles,
+		},
+		"Unknown": {
+			reason:  "An unknown release should get the newest media type.",
+			service: ServicePipelineConfigs,
+			want:    acceptPipelineConfigs,
+		},
+		"Older": {
+			reason:  "A release predating the newest media type should get the newest one it serves.",
+			service: ServiceRoles,
+			server:  ServerRelease{19, 6, 0},
+			want:    acceptRolesV2,
+		},
+		"OlderPipelineConfigs": {
+			reason:  "A release predating v11 pipeline configs should get v10.",
+			service: ServicePipelineConfigs,
+			server:  ServerRelease{20, 5, 0},
+			want:    acceptPipelineConfigsV10,
+		},
+		"TooOld": {
+			reason:  "A release predating every media type should be rejected.",
+			service: ServicePipelineConfigs,
+			server:  ServerRelease{19, 12, 0},
+			wantErr: "gocd: the pipeline_configs API requires GoCD 20.2.0 or newer, but the server runs 19.12.0",
+		},
+		"TooNew": {
+			reason:  "A release no longer serving any media type should be rejected.",
+			service: "retired",
+			server:  ServerRelease{24, 1, 0},
+			wantErr: "gocd: the retired API is not supported by this provider on GoCD 24.1.0; the newest supported release is older than 23.1.0",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := negotiate(tc.service, tc.server)
+			if tc.wantErr != "" {
+				if !IsUnsupportedVersion(err) || err.Error() != tc.wantErr {
+					t.Fatalf("\n%s\nnegotiate(...): want error %q, got %v", tc.reason, tc.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nnegotiate(...): unexpected error: %v", tc.reason, err)
+			}
+			if got != tc.want {
+				t.Errorf("\n%s\nnegotiate(...): want %q, got %q", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package gocd
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// identityKeys are the attributes, in order of preference, that identify an
+// element of a JSON array across documents.
+var identityKeys = []string{"name", "key", "id"}
+
+var marshalerType = reflect.TypeFor[json.Marshaler]()
+
+// overlay returns the JSON document desired encodes to, with the attributes of
+// the observed document raw that desired does not know about kept. An
+// attribute is known when the Go type of desired declares it, whether or not
+// desired sets it, so an attribute desired omits is removed rather than kept.
+// Elements of arrays are matched by name, key or id when they have one and by
+// position and type otherwise. Server generated links are dropped.
+func overlay(raw []byte, desired any) ([]byte, error) {
+	var observed any
+	if len(raw) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&observed); err != nil {
+			return nil, errors.Wrap(err, "cannot decode observed document")
+		}
+	}
+	out, err := overlayValue(observed, reflect.ValueOf(desired))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(out)
+}
+
+func overlayValue(observed any, v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Type().Implements(marshalerType) || reflect.PointerTo(v.Type()).Implements(marshalerType) {
+		return plain(v)
+	}
+	switch v.Kind() { //nolint:exhaustive // Other kinds encode without attributes to keep.
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return overlayValue(observed, v.Elem())
+	case reflect.Struct:
+		return overlayStruct(observed, v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return plain(v)
+		}
+		return overlaySlice(observed, v)
+	default:
+		return plain(v)
+	}
+}
+
+func overlayStruct(observed any, v reflect.Value) (any, error) {
+	o, _ := observed.(map[string]any)
+	out := make(map[string]any, len(o))
+	for k, ov := range o {
+		out[k] = ov
+	}
+	delete(out, "_links")
+	for _, f := range jsonFields(v.Type()) {
+		delete(out, f.name)
+		fv, err := v.FieldByIndexErr(f.index)
+		if err != nil || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		val, err := overlayValue(o[f.name], fv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot encode %s", f.name)
+		}
+		out[f.name] = val
+	}
+	return out, nil
+}
+
+func overlaySlice(observed any, v reflect.Value) (any, error) {
+	o, _ := observed.([]any)
+	out := make([]any, v.Len())
+	for i := range out {
+		d, err := plain(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		val, err := overlayValue(matchElement(o, i, d), v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = val
+	}
+	return out, nil
+}
+
+// matchElement returns the observed element that corresponds to the desired
+// element d at index i, or nil if there is none.
+func matchElement(observed []any, i int, d any) any {
+	dm, ok := d.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if k, id, ok := identity(dm); ok {
+		for _, e := range observed {
+			if em, ok := e.(map[string]any); ok && em[k] == id {
+				return em
+			}
+		}
+		return nil
+	}
+	if i >= len(observed) {
+		return nil
+	}
+	em, ok := observed[i].(map[string]any)
+	if !ok || em["type"] != dm["type"] {
+		return nil
+	}
+	if _, _, ok := identity(em); ok {
+		return nil
+	}
+	return em
+}
+
+func identity(m map[string]any) (string, any, bool) {
+	for _, k := range identityKeys {
+		if id, ok := m[k].(string); ok && id != "" {
+			return k, id, true
+		}
+	}
+	return "", nil, false
+}
+
+// plain returns v as encoding/json encodes it, decoded into generic values.
+func plain(v reflect.Value) (any, error) {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out any
+	return out, dec.Decode(&out)
+}
+
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// jsonFields returns the fields encoding/json encodes for a struct type,
+// including those of embedded structs.
+func jsonFields(t reflect.Type) []jsonField {
+	var out []jsonField
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for _, f := range jsonFields(ft) {
+				f.index = append([]int{i}, f.index...)
+				out = append(out, f)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		out = append(out, jsonField{name: name, index: []int{i}, omitEmpty: strings.Contains(opts, "omitempty")})
+	}
+	return out
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() { //nolint:exhaustive // Other kinds are never empty.
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
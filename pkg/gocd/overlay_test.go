@@ -0,0 +1,79 @@
+package gocd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
+)
+
+func TestOverlay(t *testing.T) {
+	cases := map[string]struct {
+		observed string
+		desired  any
+		want     string
+	}{
+		"NoObservation": {
+			desired: Role{Name: "ops", Type: "gocd", Attributes: &RoleAttributes{Users: []string{"alice"}}},
+			want:    `{"name": "ops", "type": "gocd", "attributes": {"users": ["alice"]}}`,
+		},
+		"UnknownAttributesKept": {
+			observed: `{"name": "ops", "type": "gocd", "attributes": {"users": ["bob"], "groups": ["admins"]}, "created_by": "admin", "_links": {"self": {"href": "x"}}}`,
+			desired:  Role{Name: "ops", Type: "gocd", Attributes: &RoleAttributes{Users: []string{"alice"}}},
+			want:     `{"name": "ops", "type": "gocd", "attributes": {"users": ["alice"], "groups": ["admins"]}, "created_by": "admin"}`,
+		},
+		"OmittedAttributesRemoved": {
+			observed: `{"name": "up42", "timer": {"spec": "0 0 * * * ?", "only_on_changes": true}, "label_template": "${COUNT}"}`,
+			desired:  PipelineConfig{Name: ptr.ToPtr("up42")},
+			want:     `{"name": "up42", "template": null}`,
+		},
+		"ElementsMatchedByName": {
+			observed: `{"name": "up42", "stages": [
+				{"name": "test", "fetch_materials": true, "display_order": 2},
+				{"name": "build", "fetch_materials": true, "display_order": 1}
+			]}`,
+			desired: PipelineConfig{Name: ptr.ToPtr("up42"), Stages: []PipelineConfigStage{{Name: "build"}, {Name: "deploy"}}},
+			want: `{"name": "up42", "template": null, "stages": [
+				{"name": "build", "fetch_materials": false, "clean_working_directory": false, "never_cleanup_artifacts": false, "display_order": 1,
+				 "approval": {"type": "", "authorization": {"users": null, "roles": null}}, "environment_variables": null, "jobs": null},
+				{"name": "deploy", "fetch_materials": false, "clean_working_directory": false, "never_cleanup_artifacts": false,
+				 "approval": {"type": "", "authorization": {"users": null, "roles": null}}, "environment_variables": null, "jobs": null}
+			]}`,
+		},
+		"ElementsMatchedByPositionAndType": {
+			observed: `{"name": "build", "run_instance_count": null, "timeout": 10, "tasks": [
+				{"type": "exec", "attributes": {"command": "make", "run_if": ["passed"], "working_directory": "src", "shell": "bash"}},
+				{"type": "exec", "attributes": {"command": "make", "run_if": ["passed"], "working_directory": "src", "shell": "zsh"}}
+			]}`,
+			desired: PipelineConfigStageJobs{Name: "build", Timeout: intstr.FromInt32(20), Tasks: []PipelineConfigStageJobsTask{
+				{Type: PipelineConfigStageJobsTaskTypeExec, Attributes: &PipelineConfigStageJobsTaskAttributesExec{Command: "make", RunIf: []RunIfType{RunIfTypePassed}}},
+				{Type: PipelineConfigStageJobsTaskTypeAnt, Attributes: &PipelineConfigStageJobsTaskAttributesAnt{RunIf: []RunIfType{RunIfTypePassed}}},
+			}},
+			want: `{"name": "build", "run_instance_count": null, "timeout": 20, "environment_variables": null, "resources": null, "artifacts": null, "tasks": [
+				{"type": "exec", "attributes": {"command": "make", "run_if": ["passed"], "working_directory": null, "shell": "bash"}},
+				{"type": "ant", "attributes": {"run_if": ["passed"], "build_file": "", "target": "", "working_directory": ""}}
+			]}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := overlay([]byte(tc.observed), tc.desired)
+			if err != nil {
+				t.Fatalf("overlay(...): %v", err)
+			}
+			var got, want any
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("overlay(...): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
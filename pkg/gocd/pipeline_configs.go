@@ -12,10 +12,12 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/marquesgui/provider-gocd/pkg/cmp"
+	"github.com/marquesgui/provider-gocd/pkg/ptr"
 )
 
 const (
 	acceptPipelineConfigs      = "application/vnd.go.cd.v11+json"
+	acceptPipelineConfigsV10   = "application/vnd.go.cd.v10+json"
 	pipelineConfigsServicePath = "/go/api/admin/pipelines"
 	nilStr                     = "<nil>"
 )
@@ -103,12 +105,20 @@ func (p PipelineConfigParameter) Equal(o PipelineConfigParameter) bool {
 type EnvironmentVariable struct {
 	Name           string `json:"name"`
 	Value          string `json:"value"`
-	EncryptedValue string `json:"encrypted_value"`
+	EncryptedValue string `json:"encrypted_value,omitempty"`
 	Secure         bool   `json:"secure"`
 }
 
+// Equal compares the values of plain variables and the encrypted values of
+// secure ones, which is all GoCD returns of them.
 func (e EnvironmentVariable) Equal(other EnvironmentVariable) bool {
-	return e.Name == other.Name
+	if e.Name != other.Name || e.Secure != other.Secure {
+		return false
+	}
+	if e.Secure {
+		return e.EncryptedValue == other.EncryptedValue
+	}
+	return e.Value == other.Value
 }
 
 type PipelineConfigMaterialType string
@@ -148,25 +158,9 @@ func (p *PipelineConfigMaterialFilter) Equal(other *PipelineConfigMaterialFilter
 		return p == other
 	}
 
-	ingnoreIsEqual := func() bool {
-		pIgnoreCopy := slices.Clone(p.Ignore)
-		otherIgnoreCopy := slices.Clone(other.Ignore)
-
-		slices.Sort(pIgnoreCopy)
-		slices.Sort(otherIgnoreCopy)
-
-		return slices.Equal(pIgnoreCopy, otherIgnoreCopy)
-	}()
-
-	includesIsEqual := func() bool {
-		pIncludesCopy := slices.Clone(p.Includes)
-		otherIncludesCopy := slices.Clone(other.Includes)
+	ingnoreIsEqual := cmp.ValuesEqualUnordered(p.Ignore, other.Ignore)
 
-		slices.Sort(pIncludesCopy)
-		slices.Sort(otherIncludesCopy)
-
-		return slices.Equal(pIncludesCopy, otherIncludesCopy)
-	}()
+	includesIsEqual := cmp.ValuesEqualUnordered(p.Includes, other.Includes)
 
 	return includesIsEqual && ingnoreIsEqual
 }
@@ -221,7 +215,7 @@ func (p *PipelineConfigMaterialAttributesGit) getMaterialAttrID() string {
 	if p == nil {
 		return nilStr
 	}
-	return *p.URL + *p.Branch
+	return ptr.Deref(p.URL) + ptr.Deref(p.Branch)
 }
 
 type PipelineConfigMaterialAttributesSvn struct {
@@ -247,10 +241,10 @@ func (p *PipelineConfigMaterialAttributesSvn) Equal(other PipelineConfigMaterial
 		return false
 	}
 
-	return *p.Name == *o.Name &&
-		*p.URL == *o.URL &&
-		*p.Username == *o.Username &&
-		*p.Destination == *o.Destination &&
+	return ptr.Deref(p.Name) == ptr.Deref(o.Name) &&
+		ptr.Deref(p.URL) == ptr.Deref(o.URL) &&
+		ptr.Deref(p.Username) == ptr.Deref(o.Username) &&
+		ptr.Deref(p.Destination) == ptr.Deref(o.Destination) &&
 		p.Filter.Equal(o.Filter) &&
 		p.InvertFilter == o.InvertFilter &&
 		p.AutoUpdate == o.AutoUpdate &&
@@ -262,7 +256,7 @@ func (p *PipelineConfigMaterialAttributesSvn) getMaterialAttrID() string {
 		return nilStr
 	}
 
-	return *p.URL
+	return ptr.Deref(p.URL)
 }
 
 type PipelineConfigMaterialAttributesHg struct {
@@ -288,11 +282,11 @@ func (p *PipelineConfigMaterialAttributesHg) Equal(other PipelineConfigMaterialA
 		return false
 	}
 
-	nameIsEqual := *p.Name == *o.Name
-	urlIsEqual := *p.URL == *o.URL
-	usernameIsEqual := *p.Username == *o.Username
-	branchIsEqual := *p.Branch == *o.Branch
-	destinationIsEqual := *p.Destination == *o.Destination
+	nameIsEqual := ptr.Deref(p.Name) == ptr.Deref(o.Name)
+	urlIsEqual := ptr.Deref(p.URL) == ptr.Deref(o.URL)
+	usernameIsEqual := ptr.Deref(p.Username) == ptr.Deref(o.Username)
+	branchIsEqual := ptr.Deref(p.Branch) == ptr.Deref(o.Branch)
+	destinationIsEqual := ptr.Deref(p.Destination) == ptr.Deref(o.Destination)
 	filterAreEqual := p.Filter.Equal(o.Filter)
 	InvertFilterIsEqual := p.InvertFilter == o.InvertFilter
 	autoUpdateIsEqual := p.AutoUpdate == o.AutoUpdate
@@ -312,7 +306,7 @@ func (p *PipelineConfigMaterialAttributesHg) getMaterialAttrID() string {
 		return nilStr
 	}
 
-	return *p.URL + *p.Branch
+	return ptr.Deref(p.URL) + ptr.Deref(p.Branch)
 }
 
 type PipelineConfigMaterialAttributesP4 struct {
@@ -339,12 +333,12 @@ func (p *PipelineConfigMaterialAttributesP4) Equal(other PipelineConfigMaterialA
 		return false
 	}
 
-	nameIsEqual := *p.Name == *o.Name
-	portIsEqual := *p.Port == *o.Port
+	nameIsEqual := ptr.Deref(p.Name) == ptr.Deref(o.Name)
+	portIsEqual := ptr.Deref(p.Port) == ptr.Deref(o.Port)
 	UseTicketsIsEqual := p.UseTickets == o.UseTickets
-	viewIsEqual := *p.View == *o.View
-	usernameIsEqual := *p.Username == *o.Username
-	destinationIsEqual := *p.Destination == *o.Destination
+	viewIsEqual := ptr.Deref(p.View) == ptr.Deref(o.View)
+	usernameIsEqual := ptr.Deref(p.Username) == ptr.Deref(o.Username)
+	destinationIsEqual := ptr.Deref(p.Destination) == ptr.Deref(o.Destination)
 	filterIsEqual := p.Filter.Equal(o.Filter)
 	invertFilterIsEqual := p.InvertFilter == o.InvertFilter
 	autoUpdateIsEqual := p.AutoUpdate == o.AutoUpdate
@@ -365,7 +359,7 @@ func (p *PipelineConfigMaterialAttributesP4) getMaterialAttrID() string {
 		return nilStr
 	}
 
-	return *p.Port
+	return ptr.Deref(p.Port)
 }
 
 type PipelineConfigMaterialAttributesTfs struct {
@@ -392,12 +386,12 @@ func (p *PipelineConfigMaterialAttributesTfs) Equal(other PipelineConfigMaterial
 		return false
 	}
 
-	nameIsEqual := *p.Name == *o.Name
-	urlIsEqual := *p.URL == *o.URL
-	projectPathIsEqual := *p.ProjectPath == *o.ProjectPath
-	domainIsEqual := *p.Domain == *o.Domain
-	usernameIsEqual := *p.Username == *o.Username
-	destinationIsEqual := *p.Destination == *o.Destination
+	nameIsEqual := ptr.Deref(p.Name) == ptr.Deref(o.Name)
+	urlIsEqual := ptr.Deref(p.URL) == ptr.Deref(o.URL)
+	projectPathIsEqual := ptr.Deref(p.ProjectPath) == ptr.Deref(o.ProjectPath)
+	domainIsEqual := ptr.Deref(p.Domain) == ptr.Deref(o.Domain)
+	usernameIsEqual := ptr.Deref(p.Username) == ptr.Deref(o.Username)
+	destinationIsEqual := ptr.Deref(p.Destination) == ptr.Deref(o.Destination)
 	autoUpdateIsEqual := p.AutoUpdate == o.AutoUpdate
 	filterAreEqual := p.Filter.Equal(o.Filter)
 	invertFilterIsEqual := p.InvertFilter == o.InvertFilter
@@ -417,7 +411,7 @@ func (p *PipelineConfigMaterialAttributesTfs) getMaterialAttrID() string {
 	if p == nil {
 		return nilStr
 	}
-	return *p.URL + *p.ProjectPath
+	return ptr.Deref(p.URL) + ptr.Deref(p.ProjectPath)
 }
 
 type PipelineConfigMaterialAttributesDependency struct {
@@ -438,9 +432,9 @@ func (p *PipelineConfigMaterialAttributesDependency) Equal(other PipelineConfigM
 		return false
 	}
 
-	nameIsEqual := *p.Name == *o.Name
-	pipelineIsEqual := *p.Pipeline == *o.Pipeline
-	stageIsEqual := *p.Stage == *o.Stage
+	nameIsEqual := ptr.Deref(p.Name) == ptr.Deref(o.Name)
+	pipelineIsEqual := ptr.Deref(p.Pipeline) == ptr.Deref(o.Pipeline)
+	stageIsEqual := ptr.Deref(p.Stage) == ptr.Deref(o.Stage)
 	autoUpdateIsEqual := p.AutoUpdate == o.AutoUpdate
 	ignoreForSchedulingIsEqual := p.IgnoreForScheduling == o.IgnoreForScheduling
 
@@ -455,7 +449,7 @@ func (p *PipelineConfigMaterialAttributesDependency) getMaterialAttrID() string
 	if p == nil {
 		return nilStr
 	}
-	return *p.Pipeline + *p.Stage
+	return ptr.Deref(p.Pipeline) + ptr.Deref(p.Stage)
 }
 
 type PipelineConfigMaterialAttributesPackage struct {
@@ -472,11 +466,11 @@ func (p *PipelineConfigMaterialAttributesPackage) Equal(other PipelineConfigMate
 		return false
 	}
 
-	return *p.Ref == *o.Ref
+	return ptr.Deref(p.Ref) == ptr.Deref(o.Ref)
 }
 
 func (p *PipelineConfigMaterialAttributesPackage) getMaterialAttrID() string {
-	return *p.Ref
+	return ptr.Deref(p.Ref)
 }
 
 type PipelineConfigMaterialAttributesPlugin struct {
@@ -496,14 +490,14 @@ func (p *PipelineConfigMaterialAttributesPlugin) Equal(other PipelineConfigMater
 		return false
 	}
 
-	return *p.Ref == *o.Ref &&
-		*p.Destination == *o.Destination &&
+	return ptr.Deref(p.Ref) == ptr.Deref(o.Ref) &&
+		ptr.Deref(p.Destination) == ptr.Deref(o.Destination) &&
 		p.Filter.Equal(o.Filter) &&
 		p.InvertFilter == o.InvertFilter
 }
 
 func (p *PipelineConfigMaterialAttributesPlugin) getMaterialAttrID() string {
-	return *p.Ref
+	return ptr.Deref(p.Ref)
 }
 
 type PipelineConfigMaterial struct { //nolint:recvcheck
@@ -600,25 +594,9 @@ type PipelineConfigApprovalAuthorization struct {
 }
 
 func (p PipelineConfigApprovalAuthorization) Equal(other PipelineConfigApprovalAuthorization) bool {
-	usersAreEqual := func() bool {
-		pUsersClone := slices.Clone(p.Users)
-		otherUsersClone := slices.Clone(other.Users)
-
-		slices.Sort(pUsersClone)
-		slices.Sort(otherUsersClone)
+	usersAreEqual := cmp.ValuesEqualUnordered(p.Users, other.Users)
 
-		return slices.Equal(pUsersClone, otherUsersClone)
-	}()
-
-	rolesAreEqual := func() bool {
-		pRolesClone := slices.Clone(p.Roles)
-		otherRolesClone := slices.Clone(other.Roles)
-
-		slices.Sort(pRolesClone)
-		slices.Sort(otherRolesClone)
-
-		return slices.Equal(pRolesClone, otherRolesClone)
-	}()
+	rolesAreEqual := cmp.ValuesEqualUnordered(p.Roles, other.Roles)
 
 	return usersAreEqual && rolesAreEqual
 }
@@ -727,15 +705,7 @@ func (p *PipelineConfigStageJobsTaskAttributesExec) Equal(other PipelineConfigSt
 		return false
 	}
 
-	runIfIsEqual := func() bool {
-		pRunIfClone := append(make([]RunIfType, 0, len(p.RunIf)), p.RunIf...)
-		oRunIfClone := append(make([]RunIfType, 0, len(o.RunIf)), o.RunIf...)
-
-		slices.Sort(pRunIfClone)
-		slices.Sort(oRunIfClone)
-
-		return slices.Equal(pRunIfClone, oRunIfClone)
-	}()
+	runIfIsEqual := slices.Equal(p.RunIf, o.RunIf)
 
 	commandIsEqual := p.Command == o.Command
 
@@ -765,15 +735,7 @@ func (p *PipelineConfigStageJobsTaskAttributesAnt) Equal(other PipelineConfigSta
 		return false
 	}
 
-	runIfIsEqual := func() bool {
-		pRunIfClone := append(make([]RunIfType, 0, len(p.RunIf)), p.RunIf...)
-		oRunIfClone := append(make([]RunIfType, 0, len(o.RunIf)), o.RunIf...)
-
-		slices.Sort(pRunIfClone)
-		slices.Sort(oRunIfClone)
-
-		return slices.Equal(pRunIfClone, oRunIfClone)
-	}()
+	runIfIsEqual := slices.Equal(p.RunIf, o.RunIf)
 
 	buildFileIsEqual := p.BuildFile == o.BuildFile
 	targetIsEqual := p.Target == o.Target
@@ -803,15 +765,7 @@ func (p *PipelineConfigStageJobsTaskAttributesNant) Equal(other PipelineConfigSt
 		return false
 	}
 
-	runIfIsEqual := func() bool {
-		pRunIfClone := append(make([]RunIfType, 0, len(p.RunIf)), p.RunIf...)
-		oRunIfClone := append(make([]RunIfType, 0, len(o.RunIf)), o.RunIf...)
-
-		slices.Sort(pRunIfClone)
-		slices.Sort(oRunIfClone)
-
-		return slices.Equal(pRunIfClone, oRunIfClone)
-	}()
+	runIfIsEqual := slices.Equal(p.RunIf, o.RunIf)
 
 	buildFileIsEqual := p.BuildFile == o.BuildFile
 	targetIsEqual := p.Target == o.Target
@@ -841,15 +795,7 @@ func (p *PipelineConfigStageJobsTaskAttributesRake) Equal(other PipelineConfigSt
 		return false
 	}
 
-	runIfIsEqual := func() bool {
-		pRunIfClone := append(make([]RunIfType, 0, len(p.RunIf)), p.RunIf...)
-		oRunIfClone := append(make([]RunIfType, 0, len(o.RunIf)), o.RunIf...)
-
-		slices.Sort(pRunIfClone)
-		slices.Sort(oRunIfClone)
-
-		return slices.Equal(pRunIfClone, oRunIfClone)
-	}()
+	runIfIsEqual := slices.Equal(p.RunIf, o.RunIf)
 
 	buildFileIsEqual := p.BuildFile == o.BuildFile
 	targetIsEqual := p.Target == o.Target
@@ -904,16 +850,8 @@ func (p *PipelineConfigStageJobsTaskAttributesFetch) Equal(other PipelineConfigS
 		return false
 	}
 
-	artifactOriginIsEqual := p.ArtifactID == o.ArtifactID
-	runIfIsEqual := func() bool {
-		pRunIfClone := append(make([]RunIfType, 0, len(p.RunIf)), p.RunIf...)
-		oRunIfClone := append(make([]RunIfType, 0, len(o.RunIf)), o.RunIf...)
-
-		slices.Sort(pRunIfClone)
-		slices.Sort(oRunIfClone)
-
-		return slices.Equal(pRunIfClone, oRunIfClone)
-	}()
+	artifactOriginIsEqual := p.ArtifactOrigin == o.ArtifactOrigin
+	runIfIsEqual := slices.Equal(p.RunIf, o.RunIf)
 	pipelineIsEqual := p.Pipeline == o.Pipeline
 	stageIsEqual := p.Stage == o.Stage
 	jobIsEqual := p.Job == o.Job
@@ -958,15 +896,7 @@ func (p *PipelineConfigStageJobsTaskAttributesPluggable) Equal(other PipelineCon
 		return false
 	}
 
-	runIfIsEqual := func() bool {
-		pRunIfClone := append(make([]RunIfType, 0, len(p.RunIf)), p.RunIf...)
-		oRunIfClone := append(make([]RunIfType, 0, len(o.RunIf)), o.RunIf...)
-
-		slices.Sort(pRunIfClone)
-		slices.Sort(oRunIfClone)
-
-		return slices.Equal(pRunIfClone, oRunIfClone)
-	}()
+	runIfIsEqual := slices.Equal(p.RunIf, o.RunIf)
 	puglinConfigurationIsEqual := p.PluginConfiguration.Equal(o.PluginConfiguration)
 	configurationIsEqual := cmp.SlicesEqualUnordered(p.Configuration, o.Configuration, func(c ConfigProperty) string {
 		return c.Key
@@ -1088,10 +1018,10 @@ type PipelineConfigStageJobsArtifact struct {
 
 func (p PipelineConfigStageJobsArtifact) Equal(other PipelineConfigStageJobsArtifact) bool {
 	typeIsEqual := p.Type == other.Type
-	sourceIsEqual := *p.Source == *other.Source
-	destinationIsEqual := *p.Destination == *other.Destination
-	artifactIDIsEqual := *p.ArtifactID == *other.ArtifactID
-	storeIDIsEqual := *p.StoreID == *other.StoreID
+	sourceIsEqual := ptr.Deref(p.Source) == ptr.Deref(other.Source)
+	destinationIsEqual := ptr.Deref(p.Destination) == ptr.Deref(other.Destination)
+	artifactIDIsEqual := ptr.Deref(p.ArtifactID) == ptr.Deref(other.ArtifactID)
+	storeIDIsEqual := ptr.Deref(p.StoreID) == ptr.Deref(other.StoreID)
 	configurationIsEqual := cmp.SlicesEqualUnordered(p.Configuration, other.Configuration, func(c ConfigProperty) string {
 		return c.Key
 	})
@@ -1133,12 +1063,7 @@ func (j PipelineConfigStageJobs) Equal(o PipelineConfigStageJobs) bool { //nolin
 			return e.Name + e.Value
 		},
 	)
-	resourcesAreEqual := func() bool {
-		jResourceClone := append(make([]string, 0, len(j.Resources)), j.Resources...)
-		oResourceClone := append(make([]string, 0, len(o.Resources)), o.Resources...)
-
-		return slices.Equal(jResourceClone, oResourceClone)
-	}()
+	resourcesAreEqual := cmp.ValuesEqualUnordered(j.Resources, o.Resources)
 	tasksAreEqual := slices.EqualFunc(j.Tasks, o.Tasks, func(a, b PipelineConfigStageJobsTask) bool {
 		return a.Equal(&b)
 	})
@@ -1146,7 +1071,7 @@ func (j PipelineConfigStageJobs) Equal(o PipelineConfigStageJobs) bool { //nolin
 		return t.Name
 	})
 	artifactsAreEqual := cmp.SlicesEqualUnordered(j.Artifacts, o.Artifacts, func(p PipelineConfigStageJobsArtifact) string {
-		return *p.ArtifactID
+		return ptr.Deref(p.ArtifactID)
 	})
 	elasticProfileIDIsEqual := j.ElasticProfileID == o.ElasticProfileID
 
@@ -1246,6 +1171,11 @@ type PipelineConfig struct {
 	Links                *HALLinks                   `json:"_links,omitempty"`
 }
 
+// Equal reports whether two pipelines are configured alike. Collections whose
+// order GoCD acts on compare in order: stages, which run one after another, the
+// tasks of a job, their run_if conditions and arguments. Other collections,
+// such as materials, environment variables, parameters, jobs, which run in
+// parallel, and resources, compare as sets.
 func (p *PipelineConfig) Equal(other *PipelineConfig) bool { //nolint:gocyclo
 	if p == nil || other == nil {
 		return p == other
@@ -1275,12 +1205,7 @@ func (p *PipelineConfig) Equal(other *PipelineConfig) bool { //nolint:gocyclo
 		func(m PipelineConfigMaterial) string {
 			return m.GetID()
 		})
-	stagesAreEqual := cmp.SlicesEqualUnordered(
-		p.Stages,
-		other.Stages,
-		func(s PipelineConfigStage) string {
-			return s.Name
-		})
+	stagesAreEqual := cmp.SliceEqualOrdered(p.Stages, other.Stages)
 	trackingToolsAreEquals := p.TrackingTool.Equal(other.TrackingTool)
 	timerIsEqual := p.Timer.Equal(other.Timer)
 
@@ -1304,6 +1229,10 @@ type pipelineConfigCreateRequest struct {
 }
 
 type PipelineConfigsService interface {
+	// List returns all pipelines, discovered through their pipeline groups.
+	List(ctx context.Context) ([]PipelineReference, error)
+	// Groups returns all pipeline groups.
+	Groups(ctx context.Context) ([]PipelineGroup, error)
 	Get(ctx context.Context, name string) (*PipelineConfig, string, error)
 	Create(ctx context.Context, body *PipelineConfig) (*PipelineConfig, string, error)
 	Update(ctx context.Context, etag string, body *PipelineConfig) (*PipelineConfig, string, error)
@@ -1320,7 +1249,7 @@ type pipelineConfigsService struct {
 
 func (p *pipelineConfigsService) Get(ctx context.Context, name string) (*PipelineConfig, string, error) {
 	path := fmt.Sprintf("%s/%s", pipelineConfigsServicePath, url.PathEscape(name))
-	resp, err := p.c.do(ctx, http.MethodGet, path, acceptPipelineConfigs, nil, nil)
+	resp, err := p.c.doVersioned(ctx, http.MethodGet, path, ServicePipelineConfigs, nil, nil)
 	if err != nil {
 		if IsNotFound(err) {
 			return nil, "", nil
@@ -1338,7 +1267,7 @@ func (p *pipelineConfigsService) Get(ctx context.Context, name string) (*Pipelin
 func (p *pipelineConfigsService) Create(ctx context.Context, pc *PipelineConfig) (*PipelineConfig, string, error) {
 	b := newPipelineConfigCreateRequest(pc)
 
-	resp, err := p.c.do(ctx, http.MethodPost, pipelineConfigsServicePath, acceptPipelineConfigs, nil, b)
+	resp, err := p.c.doVersioned(ctx, http.MethodPost, pipelineConfigsServicePath, ServicePipelineConfigs, nil, b)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "gocd: failed to create pipeline config")
 	}
@@ -1375,7 +1304,11 @@ func (p *pipelineConfigsService) Update(ctx context.Context, etag string, body *
 	headers := map[string]string{
 		"If-Match": etag,
 	}
-	resp, err := p.c.do(ctx, http.MethodPut, path, acceptPipelineConfigs, headers, body)
+	b, err := p.c.overlayDocument(ctx, path, ServicePipelineConfigs, body)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "gocd: failed to update pipeline config")
+	}
+	resp, err := p.c.doVersioned(ctx, http.MethodPut, path, ServicePipelineConfigs, headers, b)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "gocd: failed to update pipeline config")
 	}
@@ -1388,7 +1321,8 @@ func (p *pipelineConfigsService) Update(ctx context.Context, etag string, body *
 }
 
 func (p *pipelineConfigsService) Delete(ctx context.Context, name string) error {
-	resp, err := p.c.do(ctx, http.MethodDelete, pipelineConfigsServicePath+"/"+url.PathEscape(name), acceptPipelineConfigs, nil, nil)
+	path := pipelineConfigsServicePath + "/" + url.PathEscape(name)
+	resp, err := p.c.doVersioned(ctx, http.MethodDelete, path, ServicePipelineConfigs, nil, nil)
 	if err != nil {
 		return errors.Wrap(err, "gocd: failed to delete pipeline config")
 	}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/gocd/gocdtest"
 	"github.com/marquesgui/provider-gocd/pkg/ptr"
 )
 
@@ -217,8 +218,8 @@ func TestPipelineConfigsService_Create(t *testing.T) {
 }
 
 func TestPipelineConfigsService_Update(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("If-Match") != "old-etag" {
+	ts := httptest.NewServer(withVersion("25.3.0", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.Header.Get("If-Match") != "old-etag" {
 			t.Errorf("Expected If-Match 'old-etag', got %s", r.Header.Get("If-Match"))
 		}
 		w.Header().Set("ETag", "new-etag")
@@ -238,7 +239,7 @@ func TestPipelineConfigsService_Update(t *testing.T) {
 }
 
 func TestPipelineConfigsService_Delete(t *testing.T) {
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	ts := httptest.NewServer(withVersion("25.3.0", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 	}))
 	defer ts.Close()
@@ -250,3 +251,250 @@ func TestPipelineConfigsService_Delete(t *testing.T) {
 		t.Fatalf("PipelineConfigs.Delete returned error: %v", err)
 	}
 }
+
+func TestPipelineConfigsService_UpdatePreservesUnknownAttributes(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	if err := srv.Seed(gocdtest.Pipelines, map[string]any{
+		"name":           "up42",
+		"group":          "first",
+		"label_template": "${COUNT}",
+		"display_order":  3,
+		"materials":      []any{map[string]any{"type": "git", "attributes": map[string]any{"url": "https://example.com/up42.git", "sparse_paths": []any{"src"}}}},
+		"stages":         []any{map[string]any{"name": "build", "jobs": []any{}, "approval": map[string]any{"type": "success", "allow_only_on_success": true}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	client, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	ctx := context.Background()
+
+	pc, etag, err := client.PipelineConfigs().Get(ctx, "up42")
+	if err != nil {
+		t.Fatalf("PipelineConfigs.Get returned error: %v", err)
+	}
+	pc.LabelTemplate = ptr.ToPtr("${COUNT}-${git}")
+	pc.Stages[0].Approval.AllowOnlyOnSuccess = false
+	if _, _, err := client.PipelineConfigs().Update(ctx, etag, pc); err != nil {
+		t.Fatalf("PipelineConfigs.Update returned error: %v", err)
+	}
+
+	got, _ := srv.Get(gocdtest.Pipelines, "up42")
+	want := map[string]any{
+		"label_template": "${COUNT}-${git}",
+		"display_order":  float64(3),
+		"materials":      []any{map[string]any{"type": "git", "attributes": map[string]any{"url": "https://example.com/up42.git", "sparse_paths": []any{"src"}}}},
+		"approval":       map[string]any{"type": "success", "authorization": map[string]any{"users": nil, "roles": nil}},
+	}
+	stage := got["stages"].([]any)[0].(map[string]any)
+	attrs := got["materials"].([]any)[0].(map[string]any)["attributes"].(map[string]any)
+	if diff := cmp.Diff(want, map[string]any{
+		"label_template": got["label_template"],
+		"display_order":  got["display_order"],
+		"materials":      []any{map[string]any{"type": "git", "attributes": map[string]any{"url": attrs["url"], "sparse_paths": attrs["sparse_paths"]}}},
+		"approval":       stage["approval"],
+	}); diff != "" {
+		t.Errorf("PipelineConfigs.Update: -want, +got:\n%s", diff)
+	}
+}
+
+func TestPipelineConfigsService_UpdateReadsTheDocument(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+	if err := srv.Seed(gocdtest.Pipelines, map[string]any{
+		"name":          "up42",
+		"group":         "first",
+		"display_order": 3,
+		"materials":     []any{map[string]any{"type": "git", "attributes": map[string]any{"url": "https://example.com/up42.git"}}},
+		"stages":        []any{map[string]any{"name": "build", "jobs": []any{}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	observer, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	pc, etag, err := observer.PipelineConfigs().Get(ctx, "up42")
+	if err != nil {
+		t.Fatalf("PipelineConfigs.Get returned error: %v", err)
+	}
+
+	// Clients keep no documents, so a client that never observed the
+	// pipeline keeps its unknown attributes too.
+	updater, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	pc.LabelTemplate = ptr.ToPtr("${COUNT}-${git}")
+	if _, _, err := updater.PipelineConfigs().Update(ctx, etag, pc); err != nil {
+		t.Fatalf("PipelineConfigs.Update returned error: %v", err)
+	}
+	if got, _ := srv.Get(gocdtest.Pipelines, "up42"); got["display_order"] != float64(3) {
+		t.Errorf("PipelineConfigs.Update: want display_order kept, got %v", got["display_order"])
+	}
+}
+
+// orderedPipeline has two elements in every collection of a pipeline.
+const orderedPipeline = `{
+  "name": "up42",
+  "parameters": [{"name": "env", "value": "qa"}, {"name": "region", "value": "eu"}],
+  "environment_variables": [{"name": "A", "value": "1"}, {"name": "B", "value": "2"}],
+  "materials": [
+    {"type": "git", "attributes": {"url": "https://example.com/a.git", "filter": {"ignore": ["docs/**", "*.md"], "includes": ["src/**", "go.mod"]}}},
+    {"type": "dependency", "attributes": {"pipeline": "upstream", "stage": "build"}}
+  ],
+  "stages": [
+    {
+      "name": "build",
+      "approval": {"type": "success", "authorization": {"users": ["alice", "bob"], "roles": ["admins", "devs"]}},
+      "environment_variables": [{"name": "C", "value": "3"}, {"name": "D", "value": "4"}],
+      "jobs": [
+        {
+          "name": "compile",
+          "timeout": "never",
+          "environment_variables": [{"name": "E", "value": "5"}, {"name": "F", "value": "6"}],
+          "resources": ["linux", "docker"],
+          "tasks": [
+            {"type": "exec", "attributes": {"command": "make", "arguments": ["-j", "4"], "run_if": ["passed", "failed"]}},
+            {"type": "fetch", "attributes": {"artifact_origin": "external", "pipeline": "upstream", "stage": "build", "job": "compile", "artifact_id": "image", "run_if": ["passed"], "configuration": [{"key": "a", "value": "1"}, {"key": "b", "value": "2"}]}},
+            {"type": "pluggable_task", "attributes": {"plugin_configuration": {"id": "script", "version": "1"}, "run_if": ["passed"], "configuration": [{"key": "c", "value": "3"}, {"key": "d", "value": "4"}]}}
+          ],
+          "tabs": [{"name": "coverage", "path": "coverage/index.html"}, {"name": "lint", "path": "lint.html"}],
+          "artifacts": [
+            {"type": "build", "source": "bin", "destination": "out"},
+            {"type": "external", "artifact_id": "image", "store_id": "registry", "configuration": [{"key": "Image", "value": "up42"}, {"key": "Tag", "value": "latest"}]}
+          ]
+        },
+        {"name": "lint", "timeout": "never", "tasks": [{"type": "exec", "attributes": {"command": "golangci-lint", "run_if": ["passed"]}}]}
+      ]
+    },
+    {"name": "deploy", "approval": {"type": "manual", "authorization": {"users": [], "roles": []}}, "jobs": []}
+  ]
+}`
+
+func swap[T any](s []T) {
+	s[0], s[1] = s[1], s[0]
+}
+
+func TestPipelineConfigEqualOrder(t *testing.T) {
+	cases := map[string]struct {
+		reorder func(pc *gocd.PipelineConfig)
+		want    bool
+	}{
+		"Identical": {
+			reorder: func(*gocd.PipelineConfig) {},
+			want:    true,
+		},
+		"Parameters": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Parameters) },
+			want:    true,
+		},
+		"PipelineEnvironmentVariables": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.EnvironmentVariables) },
+			want:    true,
+		},
+		"Materials": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Materials) },
+			want:    true,
+		},
+		"MaterialFilterIgnore": {
+			reorder: func(pc *gocd.PipelineConfig) {
+				swap(pc.Materials[0].Attributes.(*gocd.PipelineConfigMaterialAttributesGit).Filter.Ignore)
+			},
+			want: true,
+		},
+		"MaterialFilterIncludes": {
+			reorder: func(pc *gocd.PipelineConfig) {
+				swap(pc.Materials[0].Attributes.(*gocd.PipelineConfigMaterialAttributesGit).Filter.Includes)
+			},
+			want: true,
+		},
+		"Stages": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages) },
+			want:    false,
+		},
+		"ApprovalUsers": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Approval.Authorization.Users) },
+			want:    true,
+		},
+		"ApprovalRoles": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Approval.Authorization.Roles) },
+			want:    true,
+		},
+		"StageEnvironmentVariables": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].EnvironmentVariables) },
+			want:    true,
+		},
+		"Jobs": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Jobs) },
+			want:    true,
+		},
+		"JobEnvironmentVariables": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Jobs[0].EnvironmentVariables) },
+			want:    true,
+		},
+		"Resources": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Jobs[0].Resources) },
+			want:    true,
+		},
+		"Tasks": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Jobs[0].Tasks) },
+			want:    false,
+		},
+		"TaskRunIf": {
+			reorder: func(pc *gocd.PipelineConfig) {
+				swap(pc.Stages[0].Jobs[0].Tasks[0].Attributes.(*gocd.PipelineConfigStageJobsTaskAttributesExec).RunIf)
+			},
+			want: false,
+		},
+		"TaskArguments": {
+			reorder: func(pc *gocd.PipelineConfig) {
+				swap(pc.Stages[0].Jobs[0].Tasks[0].Attributes.(*gocd.PipelineConfigStageJobsTaskAttributesExec).Arguments)
+			},
+			want: false,
+		},
+		"FetchTaskConfiguration": {
+			reorder: func(pc *gocd.PipelineConfig) {
+				swap(pc.Stages[0].Jobs[0].Tasks[1].Attributes.(*gocd.PipelineConfigStageJobsTaskAttributesFetch).Configuration)
+			},
+			want: true,
+		},
+		"FetchTaskArtifactOrigin": {
+			reorder: func(pc *gocd.PipelineConfig) {
+				pc.Stages[0].Jobs[0].Tasks[1].Attributes.(*gocd.PipelineConfigStageJobsTaskAttributesFetch).ArtifactOrigin = "gocd"
+			},
+			want: false,
+		},
+		"PluggableTaskConfiguration": {
+			reorder: func(pc *gocd.PipelineConfig) {
+				swap(pc.Stages[0].Jobs[0].Tasks[2].Attributes.(*gocd.PipelineConfigStageJobsTaskAttributesPluggable).Configuration)
+			},
+			want: true,
+		},
+		"Tabs": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Jobs[0].Tabs) },
+			want:    true,
+		},
+		"Artifacts": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Jobs[0].Artifacts) },
+			want:    true,
+		},
+		"ArtifactConfiguration": {
+			reorder: func(pc *gocd.PipelineConfig) { swap(pc.Stages[0].Jobs[0].Artifacts[1].Configuration) },
+			want:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var a, b gocd.PipelineConfig
+			if err := json.Unmarshal([]byte(orderedPipeline), &a); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(orderedPipeline), &b); err != nil {
+				t.Fatal(err)
+			}
+			tc.reorder(&b)
+			if got := a.Equal(&b); got != tc.want {
+				t.Errorf("Equal(...): want %t, got %t", tc.want, got)
+			}
+			if got := b.Equal(&a); got != tc.want {
+				t.Errorf("Equal(...): want %t when comparing the other way around, got %t", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package gocd
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	acceptPipelineGroups      = "application/vnd.go.cd.v1+json"
+	pipelineGroupsServicePath = "/go/api/admin/pipeline_groups"
+)
+
+// PipelineGroup is a pipeline group as returned by the pipeline group config
+// API. Only the fields needed to discover pipelines are modelled.
+// See: https://api.gocd.org/current/#pipeline-group-config
+type PipelineGroup struct {
+	Name      string              `json:"name"`
+	Pipelines []PipelineReference `json:"pipelines"`
+	Links     *HALLinks           `json:"_links,omitempty"`
+}
+
+// PipelineReference names a pipeline and the group it belongs to.
+type PipelineReference struct {
+	Name  string    `json:"name"`
+	Group string    `json:"-"`
+	Links *HALLinks `json:"_links,omitempty"`
+}
+
+// Groups returns all pipeline groups with the names of their pipelines.
+func (p *pipelineConfigsService) Groups(ctx context.Context) ([]PipelineGroup, error) {
+	groups, err := listEmbedded[PipelineGroup](ctx, p.c, pipelineGroupsServicePath, ServicePipelineGroups, "groups")
+	if err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to list pipeline groups")
+	}
+	for i := range groups {
+		for j := range groups[i].Pipelines {
+			groups[i].Pipelines[j].Group = groups[i].Name
+		}
+	}
+	return groups, nil
+}
+
+// List returns all pipelines, in group order. GoCD has no collection endpoint
+// for pipeline configs, so they are discovered through their groups.
+func (p *pipelineConfigsService) List(ctx context.Context) ([]PipelineReference, error) {
+	groups, err := p.Groups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []PipelineReference
+	for _, g := range groups {
+		out = append(out, g.Pipelines...)
+	}
+	return out, nil
+}
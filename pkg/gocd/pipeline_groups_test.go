@@ -0,0 +1,62 @@
+package gocd_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func TestPipelineConfigsService_List(t *testing.T) {
+	ts := httptest.NewServer(withVersion("25.3.0", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/go/api/admin/pipeline_groups" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Accept") != "application/vnd.go.cd.v1+json" {
+			t.Errorf("unexpected Accept header %s", r.Header.Get("Accept"))
+		}
+		fmt.Fprintln(w, `{
+		  "_links": {"self": {"href": "https://ci.example.com/go/api/admin/pipeline_groups"}},
+		  "_embedded": {
+		    "groups": [
+		      {"name": "first", "pipelines": [{"name": "up42", "_links": {"self": {"href": "https://ci.example.com/go/api/admin/pipelines/up42"}}}]},
+		      {"name": "empty", "pipelines": []},
+		      {"name": "second", "pipelines": [{"name": "build"}, {"name": "deploy"}]}
+		    ]
+		  }
+		}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	got, err := client.PipelineConfigs().List(context.Background())
+	if err != nil {
+		t.Fatalf("PipelineConfigs.List returned error: %v", err)
+	}
+	want := []gocd.PipelineReference{
+		{Name: "up42", Group: "first"},
+		{Name: "build", Group: "second"},
+		{Name: "deploy", Group: "second"},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(gocd.PipelineReference{}, "Links")); diff != "" {
+		t.Errorf("PipelineConfigs.List: -want, +got:\n%s", diff)
+	}
+}
+
+func TestPipelineConfigsService_ListMissingEmbedded(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"_embedded": {}}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	if _, err := client.PipelineConfigs().List(context.Background()); err == nil {
+		t.Error("PipelineConfigs.List: expected an error for a response without groups")
+	}
+}
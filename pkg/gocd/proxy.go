@@ -0,0 +1,41 @@
+package gocd
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc returns the function choosing the proxy of each request. Without
+// an explicit ProxyURL the HTTP_PROXY and HTTPS_PROXY environment variables
+// apply, as they do for http.DefaultTransport. The hosts in the NO_PROXY
+// environment variable and in NoProxy are reached without a proxy in either
+// case.
+func proxyFunc(cfg Config) (func(*http.Request) (*url.URL, error), error) {
+	pc := httpproxy.FromEnvironment()
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "gocd: invalid ProxyURL")
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, errors.Errorf("gocd: invalid ProxyURL: unsupported scheme %q", u.Scheme)
+		}
+		if cfg.ProxyUsername != "" {
+			u.User = url.UserPassword(cfg.ProxyUsername, cfg.ProxyPassword)
+		}
+		pc = &httpproxy.Config{HTTPProxy: u.String(), HTTPSProxy: u.String(), NoProxy: pc.NoProxy}
+	}
+	noProxy := cfg.NoProxy
+	if pc.NoProxy != "" {
+		noProxy = append([]string{pc.NoProxy}, noProxy...)
+	}
+	pc.NoProxy = strings.Join(noProxy, ",")
+	fn := pc.ProxyFunc()
+	return func(r *http.Request) (*url.URL, error) { return fn(r.URL) }, nil
+}
@@ -0,0 +1,177 @@
+package gocd_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+// proxy is a local stand-in for an egress proxy: it answers the requests
+// sent to it for any host as the GoCD server would.
+type proxy struct {
+	*httptest.Server
+	requests []*http.Request
+}
+
+func newProxy(t *testing.T) *proxy {
+	t.Helper()
+	p := &proxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.requests = append(p.requests, r)
+		if !r.URL.IsAbs() {
+			http.Error(w, "not a proxy request", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `{"version": "25.3.0"}`)
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func TestClient_Proxy(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		env      func(proxyURL string) string
+		cfg      func(proxyURL string) gocd.Config
+		wantAuth string
+	}{
+		"Environment": {
+			reason: "HTTP_PROXY should be used when no proxy is configured.",
+			env:    func(proxyURL string) string { return proxyURL },
+			cfg: func(string) gocd.Config {
+				return gocd.Config{BaseURL: "http://gocd.example.com"}
+			},
+		},
+		"Explicit": {
+			reason: "An explicit proxy should be used over the environment.",
+			env:    func(string) string { return "http://unreachable.invalid:3128" },
+			cfg: func(proxyURL string) gocd.Config {
+				return gocd.Config{BaseURL: "http://gocd.example.com", ProxyURL: proxyURL}
+			},
+		},
+		"ExplicitWithCredentials": {
+			reason: "Proxy credentials should be sent in the Proxy-Authorization header.",
+			cfg: func(proxyURL string) gocd.Config {
+				return gocd.Config{BaseURL: "http://gocd.example.com", ProxyURL: proxyURL, ProxyUsername: "egress", ProxyPassword: "s3cr3t"}
+			},
+			wantAuth: "Basic " + base64.StdEncoding.EncodeToString([]byte("egress:s3cr3t")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := newProxy(t)
+			t.Setenv("HTTP_PROXY", "")
+			t.Setenv("NO_PROXY", "")
+			if tc.env != nil {
+				t.Setenv("HTTP_PROXY", tc.env(p.URL))
+			}
+
+			client, err := gocd.New(tc.cfg(p.URL))
+			if err != nil {
+				t.Fatalf("\n%s\nNew(...): unexpected error: %v", tc.reason, err)
+			}
+			if _, err := client.Server().Version(context.Background()); err != nil {
+				t.Fatalf("\n%s\nServer.Version(...): unexpected error: %v", tc.reason, err)
+			}
+			if len(p.requests) != 1 {
+				t.Fatalf("\n%s\nExpected 1 request through the proxy, got %d", tc.reason, len(p.requests))
+			}
+			r := p.requests[0]
+			if r.URL.Host != "gocd.example.com" {
+				t.Errorf("\n%s\nExpected a request for gocd.example.com, got %s", tc.reason, r.URL)
+			}
+			if got := r.Header.Get("Proxy-Authorization"); got != tc.wantAuth {
+				t.Errorf("\n%s\nProxy-Authorization: want %q, got %q", tc.reason, tc.wantAuth, got)
+			}
+		})
+	}
+}
+
+func TestClient_NoProxy(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		env     string
+		host    string
+		proxied bool
+	}{
+		"NoProxy": {
+			reason: "Hosts in NoProxy should be reached without the proxy.",
+			host:   "gocd.internal.example.com",
+		},
+		"Environment": {
+			reason: "Hosts in the NO_PROXY environment variable should be reached without the explicit proxy.",
+			env:    ".env.example.com",
+			host:   "gocd.env.example.com",
+		},
+		"EnvironmentAndNoProxy": {
+			reason: "NoProxy should extend the NO_PROXY environment variable.",
+			env:    ".env.example.com",
+			host:   "gocd.internal.example.com",
+		},
+		"Proxied": {
+			reason:  "Hosts in neither list should be reached through the proxy.",
+			env:     ".env.example.com",
+			host:    "gocd.example.com",
+			proxied: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"version": "25.3.0"}`)
+			}))
+			defer ts.Close()
+			p := newProxy(t)
+			t.Setenv("NO_PROXY", tc.env)
+
+			// Loopback addresses are never proxied, so the server is given a
+			// name and the requests that bypass the proxy are sent to its
+			// address.
+			client, err := gocd.New(gocd.Config{
+				BaseURL:       "http://" + tc.host,
+				ProxyURL:      p.URL,
+				NoProxy:       []string{".internal.example.com"},
+				WrapTransport: rewriteHost(ts.Listener.Addr().String()),
+			})
+			if err != nil {
+				t.Fatalf("\n%s\nNew(...): unexpected error: %v", tc.reason, err)
+			}
+			if _, err := client.Server().Version(context.Background()); err != nil {
+				t.Fatalf("\n%s\nServer.Version(...): unexpected error: %v", tc.reason, err)
+			}
+			if got := len(p.requests) == 1; got != tc.proxied {
+				t.Errorf("\n%s\nproxied: want %t, got %d proxied requests", tc.reason, tc.proxied, len(p.requests))
+			}
+		})
+	}
+}
+
+// rewriteHost sends requests for hosts the proxy is bypassed for to addr.
+func rewriteHost(addr string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		tr := next.(*http.Transport).Clone()
+		proxy := tr.Proxy
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			u, err := proxy(r)
+			if err != nil {
+				return nil, err
+			}
+			if u == nil {
+				r = r.Clone(r.Context())
+				r.URL.Host = addr
+			}
+			return tr.RoundTrip(r)
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,85 @@
+package gocd
+
+import (
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limitedTransport limits the rate and concurrency of requests sent through
+// it. GoCD serializes config saves, so flooding it only makes every request
+// slower.
+type limitedTransport struct {
+	next    http.RoundTripper
+	name    string
+	limiter *rate.Limiter
+	sem     chan struct{}
+}
+
+func newLimitedTransport(next http.RoundTripper, name string, rps float64, burst, maxInFlight int) http.RoundTripper {
+	if rps <= 0 && maxInFlight <= 0 {
+		return next
+	}
+	t := &limitedTransport{next: next, name: name}
+	if rps > 0 {
+		if burst <= 0 {
+			burst = max(int(rps), 1)
+		}
+		t.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+	if maxInFlight > 0 {
+		t.sem = make(chan struct{}, maxInFlight)
+	}
+	return t
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	release := func() {}
+	if t.sem != nil {
+		select {
+		case t.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-t.sem }) }
+	}
+	rateLimitWaitSeconds.WithLabelValues(t.name).Observe(time.Since(start).Seconds())
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// The request is in flight until its body has been consumed.
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// CloseIdleConnections forwards to the wrapped transport so http.Client can
+// still release pooled connections.
+func (t *limitedTransport) CloseIdleConnections() {
+	if ic, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		ic.CloseIdleConnections()
+	}
+}
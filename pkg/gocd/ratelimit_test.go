@@ -0,0 +1,84 @@
+package gocd_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func TestClient_MaxInFlight(t *testing.T) {
+	var cur, peak int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&cur, 1)
+		defer atomic.AddInt32(&cur, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprintln(w, `{"version": "25.3.0"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL, MaxInFlight: 2})
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Server().Version(context.Background()); err != nil {
+				t.Errorf("Server.Version returned error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak)
+	}
+}
+
+func TestClient_RequestsPerSecond(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"version": "25.3.0"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL, RequestsPerSecond: 20, Burst: 1})
+	start := time.Now()
+	for range 5 {
+		if _, err := client.Server().Version(context.Background()); err != nil {
+			t.Fatalf("Server.Version returned error: %v", err)
+		}
+	}
+	// The first request uses the burst, the other four wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("Expected requests to be rate limited, took %s", elapsed)
+	}
+}
+
+func TestClient_RateLimitHonoursContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"version": "25.3.0"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL, RequestsPerSecond: 1, Burst: 1})
+	if _, err := client.Server().Version(context.Background()); err != nil {
+		t.Fatalf("Server.Version returned error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.Server().Version(ctx); err == nil {
+		t.Errorf("Expected an error when the context expires while waiting for the rate limiter")
+	}
+}
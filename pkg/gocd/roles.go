@@ -11,6 +11,7 @@ import (
 
 const (
 	acceptRoles     = "application/vnd.go.cd.v3+json" // API media type for Roles; version can be adjusted as needed.
+	acceptRolesV2   = "application/vnd.go.cd.v2+json"
 	roleServicePath = "/go/api/admin/security/roles"
 )
 
@@ -20,6 +21,7 @@ const (
 // All methods return the ETag from the server when applicable.
 //
 // Accepted status codes:
+// - List: 200
 // - Get: 200, 404 (returns nil, "", nil)
 // - Create: 200 or 201
 // - Update: 200
@@ -29,6 +31,7 @@ const (
 // - Update/Delete can include If-Match header when provided to handle concurrency.
 // - The returned ETag (if any) is the value from the response header.
 type RolesService interface {
+	List(ctx context.Context) ([]Role, error)
 	Get(ctx context.Context, name string) (*Role, string, error)
 	Create(ctx context.Context, role Role) (*Role, string, error)
 	Update(ctx context.Context, name string, role Role, etag string) (*Role, string, error)
@@ -72,8 +75,17 @@ type rolesService struct{ c *client }
 
 func (c *client) Roles() RolesService { return &rolesService{c: c} }
 
+func (s *rolesService) List(ctx context.Context) ([]Role, error) {
+	roles, err := listEmbedded[Role](ctx, s.c, roleServicePath, ServiceRoles, "roles")
+	if err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to list roles")
+	}
+	return roles, nil
+}
+
 func (s *rolesService) Get(ctx context.Context, name string) (*Role, string, error) {
-	resp, err := s.c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s", roleServicePath, url.PathEscape(name)), acceptRoles, nil, nil)
+	path := fmt.Sprintf("%s/%s", roleServicePath, url.PathEscape(name))
+	resp, err := s.c.doVersioned(ctx, http.MethodGet, path, ServiceRoles, nil, nil)
 	if err != nil {
 		if IsNotFound(err) {
 			return nil, "", nil
@@ -89,7 +101,7 @@ func (s *rolesService) Get(ctx context.Context, name string) (*Role, string, err
 }
 
 func (s *rolesService) Create(ctx context.Context, role Role) (*Role, string, error) {
-	resp, err := s.c.do(ctx, http.MethodPost, roleServicePath, acceptRoles, nil, role)
+	resp, err := s.c.doVersioned(ctx, http.MethodPost, roleServicePath, ServiceRoles, nil, role)
 	if err != nil {
 		return nil, "", err
 	}
@@ -106,7 +118,11 @@ func (s *rolesService) Update(ctx context.Context, name string, role Role, etag
 	headers := map[string]string{
 		"If-Match": etag,
 	}
-	resp, err := s.c.do(ctx, http.MethodPut, path, acceptRoles, headers, role)
+	body, err := s.c.overlayDocument(ctx, path, ServiceRoles, role)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "gocd: failed to update role")
+	}
+	resp, err := s.c.doVersioned(ctx, http.MethodPut, path, ServiceRoles, headers, body)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "gocd: failed to update role")
 	}
@@ -124,7 +140,7 @@ func (s *rolesService) Delete(ctx context.Context, name string, etag string) err
 	if etag != "" {
 		headers["If-Match"] = etag
 	}
-	resp, err := s.c.do(ctx, http.MethodDelete, path, acceptRoles, headers, nil)
+	resp, err := s.c.doVersioned(ctx, http.MethodDelete, path, ServiceRoles, headers, nil)
 	if err != nil {
 		return err
 	}
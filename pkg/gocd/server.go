@@ -0,0 +1,92 @@
+package gocd
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	acceptServer            = "application/vnd.go.cd.v1+json"
+	acceptSystemAdmins      = "application/vnd.go.cd.v2+json"
+	versionServicePath      = "/go/api/version"
+	currentUserServicePath  = "/go/api/current_user"
+	systemAdminsServicePath = "/go/api/admin/security/system_admins"
+)
+
+// ServerService exposes information about the GoCD server itself and the
+// identity the client is authenticated as. It is used for health checking.
+// See: https://api.gocd.org/current/#version and https://api.gocd.org/current/#current-user
+type ServerService interface {
+	// Version returns the version of the GoCD server. The endpoint does not require authentication.
+	Version(ctx context.Context) (*ServerVersion, error)
+	// CurrentUser returns the user the client is authenticated as.
+	CurrentUser(ctx context.Context) (*CurrentUser, error)
+	// IsAdmin reports whether the authenticated user is a system administrator.
+	IsAdmin(ctx context.Context) (bool, error)
+}
+
+// ServerVersion represents the payload of the GoCD version API.
+type ServerVersion struct {
+	Version     string    `json:"version"`
+	BuildNumber string    `json:"build_number"`
+	GitSHA      string    `json:"git_sha"`
+	FullVersion string    `json:"full_version"`
+	CommitURL   string    `json:"commit_url"`
+	Links       *HALLinks `json:"_links,omitempty"`
+}
+
+// CurrentUser represents the payload of the GoCD current user API.
+type CurrentUser struct {
+	LoginName   string    `json:"login_name"`
+	DisplayName string    `json:"display_name"`
+	Enabled     bool      `json:"enabled"`
+	Email       string    `json:"email,omitempty"`
+	Links       *HALLinks `json:"_links,omitempty"`
+}
+
+type serverService struct{ c *client }
+
+func (c *client) Server() ServerService { return &serverService{c: c} }
+
+func (s *serverService) Version(ctx context.Context) (*ServerVersion, error) {
+	resp, err := s.c.do(ctx, http.MethodGet, versionServicePath, acceptServer, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to get server version")
+	}
+	var out ServerVersion
+	if err := decodeJSON(resp, &out); err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to decode response")
+	}
+	// API media types are negotiated for the release last reported.
+	if r, err := ParseServerRelease(out.Version); err == nil {
+		s.c.setRelease(r)
+	}
+	return &out, nil
+}
+
+func (s *serverService) CurrentUser(ctx context.Context) (*CurrentUser, error) {
+	resp, err := s.c.do(ctx, http.MethodGet, currentUserServicePath, acceptServer, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to get current user")
+	}
+	var out CurrentUser
+	if err := decodeJSON(resp, &out); err != nil {
+		return nil, errors.Wrap(err, "gocd: failed to decode response")
+	}
+	return &out, nil
+}
+
+func (s *serverService) IsAdmin(ctx context.Context) (bool, error) {
+	// Only system administrators may read the list of system administrators,
+	// so a 403 is the answer rather than an error.
+	resp, err := s.c.do(ctx, http.MethodGet, systemAdminsServicePath, acceptSystemAdmins, nil, nil)
+	if err != nil {
+		if IsForbidden(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "gocd: failed to get system admins")
+	}
+	return true, resp.Body.Close()
+}
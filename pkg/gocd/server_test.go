@@ -0,0 +1,85 @@
+package gocd_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+func TestServerService_Version(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/go/api/version" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		fmt.Fprintln(w, `{"version": "25.3.0", "build_number": "20935", "full_version": "25.3.0 (20935-abc)"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	v, err := client.Server().Version(context.Background())
+	if err != nil {
+		t.Fatalf("Server.Version returned error: %v", err)
+	}
+	if v.FullVersion != "25.3.0 (20935-abc)" {
+		t.Errorf("Expected full version '25.3.0 (20935-abc)', got %s", v.FullVersion)
+	}
+}
+
+func TestServerService_CurrentUser(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintln(w, `{"login_name": "admin", "display_name": "Admin", "enabled": true}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL, Username: "admin", Password: "secret"})
+	u, err := client.Server().CurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("Server.CurrentUser returned error: %v", err)
+	}
+	if u.LoginName != "admin" {
+		t.Errorf("Expected login name 'admin', got %s", u.LoginName)
+	}
+
+	anonymous, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	if _, err := anonymous.Server().CurrentUser(context.Background()); err == nil {
+		t.Errorf("Expected error for unauthenticated request")
+	}
+}
+
+func TestServerService_IsAdmin(t *testing.T) {
+	cases := map[string]struct {
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		"Admin":    {status: http.StatusOK, want: true},
+		"NotAdmin": {status: http.StatusForbidden, want: false},
+		"Error":    {status: http.StatusInternalServerError, wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprintln(w, `{}`)
+			}))
+			defer ts.Close()
+
+			client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+			got, err := client.Server().IsAdmin(context.Background())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Server.IsAdmin error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Server.IsAdmin = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
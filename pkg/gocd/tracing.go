@@ -0,0 +1,38 @@
+package gocd
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/marquesgui/provider-gocd/pkg/gocd"
+
+// tracer returns the tracer of the GoCD client. It is looked up on every call
+// so a tracer provider installed after the client was built is honoured.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// endSpan records the outcome of a GoCD API request on its span and ends it.
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	var apiErr *APIError
+	switch {
+	case resp != nil:
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			span.SetAttributes(attribute.String("gocd.etag", etag))
+		}
+	case errors.As(err, &apiErr):
+		span.SetAttributes(attribute.Int("http.response.status_code", apiErr.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
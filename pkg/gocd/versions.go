@@ -0,0 +1,190 @@
+package gocd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the versioned GoCD API services.
+const (
+	ServiceRoles                       = "roles"
+	ServiceAuthorizationConfigurations = "authorization_configurations"
+	ServiceElasticAgentProfiles        = "elastic_agent_profiles"
+	ServicePipelineConfigs             = "pipeline_configs"
+	ServicePipelineGroups              = "pipeline_groups"
+	ServiceEncryption                  = "encryption"
+)
+
+// A mediaTypeRange is an API media type and the GoCD releases that serve it.
+type mediaTypeRange struct {
+	mediaType string
+	since     ServerRelease // First release serving the media type.
+	until     ServerRelease // First release no longer serving it; zero if still served.
+}
+
+// compatibility lists, newest first, the media types this client can speak
+// for each service. Payloads are only modelled for these versions, so a
+// server outside every range is rejected rather than sent an Accept header it
+// would answer with a 404.
+// See: https://api.gocd.org/current/#api-changelog
+var compatibility = map[string][]mediaTypeRange{
+	ServiceRoles: {
+		{mediaType: acceptRoles, since: ServerRelease{19, 11, 0}},
+		{mediaType: acceptRolesV2, since: ServerRelease{18, 7, 0}, until: ServerRelease{20, 2, 0}},
+	},
+	ServiceAuthorizationConfigurations: {{mediaType: acceptAuthzCfg, since: ServerRelease{19, 6, 0}}},
+	ServiceElasticAgentProfiles:        {{mediaType: acceptElasticAgentProfile, since: ServerRelease{19, 3, 0}}},
+	ServicePipelineConfigs: {
+		{mediaType: acceptPipelineConfigs, since: ServerRelease{20, 8, 0}},
+		{mediaType: acceptPipelineConfigsV10, since: ServerRelease{20, 2, 0}, until: ServerRelease{21, 1, 0}},
+	},
+	ServicePipelineGroups: {{mediaType: acceptPipelineGroups, since: ServerRelease{19, 2, 0}}},
+	ServiceEncryption:     {{mediaType: acceptEncryption, since: ServerRelease{17, 1, 0}}},
+}
+
+// A ServerRelease is a GoCD release number such as 24.1.0.
+type ServerRelease [3]int
+
+// ParseServerRelease parses the version reported by the GoCD version API.
+func ParseServerRelease(s string) (ServerRelease, error) {
+	var r ServerRelease
+	parts := strings.SplitN(strings.TrimSpace(s), ".", 4)
+	if len(parts) < 2 {
+		return r, errors.Errorf("gocd: invalid server version %q", s)
+	}
+	for i := 0; i < len(parts) && i < 3; i++ {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return r, errors.Errorf("gocd: invalid server version %q", s)
+		}
+		r[i] = n
+	}
+	return r, nil
+}
+
+func (r ServerRelease) String() string {
+	return fmt.Sprintf("%d.%d.%d", r[0], r[1], r[2])
+}
+
+// IsZero returns true if the release is unset.
+func (r ServerRelease) IsZero() bool {
+	return r == ServerRelease{}
+}
+
+// Less returns true if r is older than o.
+func (r ServerRelease) Less(o ServerRelease) bool {
+	for i := range r {
+		if r[i] != o[i] {
+			return r[i] < o[i]
+		}
+	}
+	return false
+}
+
+// UnsupportedVersionError is returned when no media type this client knows is
+// served by the GoCD server for a service.
+type UnsupportedVersionError struct {
+	Service string
+	Server  ServerRelease
+	Oldest  ServerRelease
+	Newest  ServerRelease // Zero if newer servers are supported.
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	if e.Server.Less(e.Oldest) {
+		return fmt.Sprintf("gocd: the %s API requires GoCD %s or newer, but the server runs %s", e.Service, e.Oldest, e.Server)
+	}
+	return fmt.Sprintf("gocd: the %s API is not supported by this provider on GoCD %s; the newest supported release is older than %s", e.Service, e.Server, e.Newest)
+}
+
+// IsUnsupportedVersion returns true if the error is an UnsupportedVersionError.
+func IsUnsupportedVersion(err error) bool {
+	var uv *UnsupportedVersionError
+	return errors.As(err, &uv)
+}
+
+// negotiate returns the newest media type of the service served by the
+// supplied release. An unknown (zero) release, e.g. a development build,
+// gets the newest media type.
+func negotiate(service string, server ServerRelease) (string, error) {
+	ranges, ok := compatibility[service]
+	if !ok {
+		return "", errors.Errorf("gocd: unknown service %q", service)
+	}
+	if server.IsZero() {
+		return ranges[0].mediaType, nil
+	}
+	for _, r := range ranges {
+		if server.Less(r.since) {
+			continue
+		}
+		if !r.until.IsZero() && !server.Less(r.until) {
+			continue
+		}
+		return r.mediaType, nil
+	}
+	return "", &UnsupportedVersionError{
+		Service: service,
+		Server:  server,
+		Oldest:  ranges[len(ranges)-1].since,
+		Newest:  ranges[0].until,
+	}
+}
+
+// knownRelease returns the release of the GoCD server if it was detected.
+func (c *client) knownRelease() (ServerRelease, bool) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	return c.version, c.versionKnown
+}
+
+// setRelease records the release of the GoCD server, as reported by its
+// version API.
+func (c *client) setRelease(v ServerRelease) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	c.version, c.versionKnown = v, true
+}
+
+// serverRelease returns the release of the GoCD server, detecting it if no
+// version request succeeded yet. It is zero if the server reports a version
+// that is not a release number, e.g. a development build, which is detected
+// again on the next call. It is an error if the server does not answer the
+// version API.
+//
+// Clients live as long as the provider, so the release is refreshed by every
+// later version request, e.g. the periodic health check of the
+// ProviderConfig, to follow upgrades and downgrades of the server.
+func (c *client) serverRelease(ctx context.Context) (ServerRelease, error) {
+	if v, ok := c.knownRelease(); ok {
+		return v, nil
+	}
+	sv, err := c.Server().Version(ctx)
+	if err != nil {
+		return ServerRelease{}, errors.Wrap(err, "gocd: cannot detect server version")
+	}
+	v, _ := ParseServerRelease(sv.Version)
+	return v, nil
+}
+
+// doVersioned is like do, but sends the Accept header of the service served
+// by the release of the server, which is detected before the first request.
+// A server too old or too new for the service is rejected with an
+// UnsupportedVersionError rather than sent a media type it would answer with
+// a 404.
+func (c *client) doVersioned(ctx context.Context, method, path, service string, headers map[string]string, body any) (*http.Response, error) {
+	v, err := c.serverRelease(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accept, err := negotiate(service, v)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, method, path, accept, headers, body)
+}
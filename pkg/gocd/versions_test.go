@@ -0,0 +1,211 @@
+package gocd_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+	"github.com/marquesgui/provider-gocd/pkg/gocd/gocdtest"
+)
+
+// withVersion answers the version API with the supplied release and passes
+// every other request to h.
+func withVersion(release string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/go/api/version" {
+			fmt.Fprintf(w, `{"version": %q}`, release)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func TestParseServerRelease(t *testing.T) {
+	cases := map[string]struct {
+		in      string
+		want    gocd.ServerRelease
+		wantErr bool
+	}{
+		"Release":     {in: "25.3.0", want: gocd.ServerRelease{25, 3, 0}},
+		"MajorMinor":  {in: "19.11", want: gocd.ServerRelease{19, 11, 0}},
+		"ExtraSuffix": {in: "24.1.0.1", want: gocd.ServerRelease{24, 1, 0}},
+		"Garbage":     {in: "dev", wantErr: true},
+		"Empty":       {in: "", wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := gocd.ParseServerRelease(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseServerRelease(%q): unexpected error %v", tc.in, err)
+			}
+			if got != tc.want {
+				t.Errorf("ParseServerRelease(%q): want %s, got %s", tc.in, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNegotiationServerTooOld(t *testing.T) {
+	srv := gocdtest.NewServer(gocdtest.WithVersion("18.1.0"))
+	defer srv.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	_, _, err := client.Roles().Create(context.Background(), gocd.Role{Name: "devs", Type: "gocd"})
+	if !gocd.IsUnsupportedVersion(err) {
+		t.Fatalf("Roles.Create: want unsupported version error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "18.7.0") || !strings.Contains(err.Error(), "18.1.0") {
+		t.Errorf("Roles.Create: error should name required and actual release, got %q", err)
+	}
+
+	if got := srv.Requests(); len(got) != 1 || got[0].Path != "/go/api/version" {
+		t.Errorf("Roles.Create: want only the server version requested, got %v", got)
+	}
+
+	// The detected release is cached, so later calls fail without a request.
+	before := len(srv.Requests())
+	if _, _, err := client.Roles().Get(context.Background(), "devs"); !gocd.IsUnsupportedVersion(err) {
+		t.Errorf("Roles.Get: want unsupported version error, got %v", err)
+	}
+	if got := len(srv.Requests()); got != before {
+		t.Errorf("Roles.Get: want no further requests, got %d", got-before)
+	}
+}
+
+func TestNegotiationMissingResource(t *testing.T) {
+	srv := gocdtest.NewServer()
+	defer srv.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: srv.URL})
+	for i := 0; i < 2; i++ {
+		r, _, err := client.Roles().Get(context.Background(), "missing")
+		if err != nil || r != nil {
+			t.Fatalf("Roles.Get: want nil role and error, got %v, %v", r, err)
+		}
+	}
+
+	versions := 0
+	for _, r := range srv.Requests() {
+		if r.Path == "/go/api/version" {
+			versions++
+		}
+	}
+	if versions != 1 {
+		t.Errorf("want the server version to be detected once, got %d requests", versions)
+	}
+}
+
+func TestNegotiationOlderMediaType(t *testing.T) {
+	var paths, accepts []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths, accepts = append(paths, r.URL.Path), append(accepts, r.Header.Get("Accept"))
+		if r.URL.Path == "/go/api/version" {
+			fmt.Fprintln(w, `{"version": "19.6.0"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"name": "devs", "type": "gocd"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	if _, _, err := client.Roles().Get(context.Background(), "devs"); err != nil {
+		t.Fatalf("Roles.Get: unexpected error: %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "/go/api/version" {
+		t.Fatalf("Roles.Get: want the server version detected before the first request, got %v", paths)
+	}
+	if accepts[1] != "application/vnd.go.cd.v2+json" {
+		t.Errorf("Roles.Get: want the v2 media type served by GoCD 19.6.0, got %q", accepts[1])
+	}
+}
+
+func TestNegotiationVersionUnavailable(t *testing.T) {
+	var paths []string
+	status := http.StatusServiceUnavailable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/go/api/version" {
+			w.WriteHeader(status)
+			fmt.Fprintln(w, `{"version": "18.1.0"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"name": "devs", "type": "gocd"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	_, _, err := client.Roles().Get(context.Background(), "devs")
+	if err == nil || gocd.IsUnsupportedVersion(err) {
+		t.Fatalf("Roles.Get: want an error detecting the server version, got %v", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("Roles.Get: want only the server version requested, got %v", paths)
+	}
+
+	// A failed detection is not cached, so the release is detected once the
+	// server answers.
+	status = http.StatusOK
+	if _, _, err := client.Roles().Get(context.Background(), "devs"); !gocd.IsUnsupportedVersion(err) {
+		t.Errorf("Roles.Get: want unsupported version error, got %v", err)
+	}
+}
+
+func TestNegotiationReleaseRefreshed(t *testing.T) {
+	var accepts []string
+	release := "25.3.0"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/go/api/version" {
+			fmt.Fprintf(w, `{"version": %q}`, release)
+			return
+		}
+		accepts = append(accepts, r.Header.Get("Accept"))
+		fmt.Fprintln(w, `{"name": "devs", "type": "gocd"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	if _, _, err := client.Roles().Get(context.Background(), "devs"); err != nil {
+		t.Fatalf("Roles.Get: unexpected error: %v", err)
+	}
+
+	// The server is downgraded; the next version request, e.g. a health
+	// check, refreshes the release media types are negotiated for.
+	release = "19.6.0"
+	if _, err := client.Server().Version(context.Background()); err != nil {
+		t.Fatalf("Server.Version: unexpected error: %v", err)
+	}
+	if _, _, err := client.Roles().Get(context.Background(), "devs"); err != nil {
+		t.Fatalf("Roles.Get: unexpected error: %v", err)
+	}
+	want := []string{"application/vnd.go.cd.v3+json", "application/vnd.go.cd.v2+json"}
+	if len(accepts) != 2 || accepts[0] != want[0] || accepts[1] != want[1] {
+		t.Errorf("Roles.Get: want Accept headers %v, got %v", want, accepts)
+	}
+}
+
+func TestNegotiationDevelopmentBuild(t *testing.T) {
+	versions := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/go/api/version" {
+			versions++
+			fmt.Fprintln(w, `{"version": "dev"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"name": "devs", "type": "gocd"}`)
+	}))
+	defer ts.Close()
+
+	client, _ := gocd.New(gocd.Config{BaseURL: ts.URL})
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Roles().Get(context.Background(), "devs"); err != nil {
+			t.Fatalf("Roles.Get: want the newest media type for an unknown release, got %v", err)
+		}
+	}
+	if versions != 2 {
+		t.Errorf("want an unknown release detected again, got %d version requests", versions)
+	}
+}
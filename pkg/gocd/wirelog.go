@@ -0,0 +1,123 @@
+package gocd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// DefaultMaxLogBodyBytes is the size bodies are truncated at in the wire log.
+const DefaultMaxLogBodyBytes = 4096
+
+// Headers whose values never appear in the wire log.
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// wireLogTransport logs the requests sent through it and their responses,
+// with credentials redacted, so that a request GoCD rejects can be inspected.
+type wireLogTransport struct {
+	next    http.RoundTripper
+	log     logging.Logger
+	maxBody int
+}
+
+func newWireLogTransport(next http.RoundTripper, log logging.Logger, maxBody int) http.RoundTripper {
+	if log == nil {
+		return next
+	}
+	if maxBody <= 0 {
+		maxBody = DefaultMaxLogBodyBytes
+	}
+	return &wireLogTransport{next: next, log: log, maxBody: maxBody}
+}
+
+func (t *wireLogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	kv := []any{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"latency", time.Since(start).String(),
+		"requestHeaders", redactHeaders(req.Header),
+		"requestBody", t.requestBody(req, reqBody),
+	}
+	if err != nil {
+		t.log.Info("GoCD request failed", append(kv, "error", err)...)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		t.log.Info("GoCD request failed", append(kv, "status", resp.StatusCode, "error", err)...)
+		return nil, err
+	}
+	t.log.Info("GoCD request",
+		append(kv, "status", resp.StatusCode, "responseHeaders", redactHeaders(resp.Header), "responseBody", t.body(respBody))...)
+	return resp, nil
+}
+
+// CloseIdleConnections forwards to the wrapped transport so http.Client can
+// still release pooled connections.
+func (t *wireLogTransport) CloseIdleConnections() {
+	if ic, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		ic.CloseIdleConnections()
+	}
+}
+
+// requestBody returns a request body as logged. The body of an encryption
+// request is the plain text value being encrypted, so it is never logged.
+func (t *wireLogTransport) requestBody(req *http.Request, b []byte) string {
+	if strings.HasSuffix(req.URL.Path, encryptionServicePath) && len(bytes.TrimSpace(b)) > 0 {
+		return Redacted
+	}
+	return t.body(b)
+}
+
+// body returns a body as logged: JSON with credentials redacted, truncated
+// at the configured size.
+func (t *wireLogTransport) body(b []byte) string {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err == nil {
+		if out, err := json.Marshal(scrub(v)); err == nil {
+			b = out
+		}
+	}
+	if len(b) <= t.maxBody {
+		return string(b)
+	}
+	return fmt.Sprintf("%s... (%d bytes truncated)", b[:t.maxBody], len(b)-t.maxBody)
+}
+
+func redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k := range h {
+		out[k] = h.Get(k)
+	}
+	for _, k := range redactedHeaders {
+		if _, ok := out[k]; ok {
+			out[k] = Redacted
+		}
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package gocd_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/marquesgui/provider-gocd/pkg/gocd"
+)
+
+// logRecorder records the key value pairs logged through it.
+type logRecorder struct {
+	entries []map[string]any
+}
+
+func (l *logRecorder) Info(_ string, kv ...any) {
+	e := map[string]any{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		e[fmt.Sprint(kv[i])] = kv[i+1]
+	}
+	l.entries = append(l.entries, e)
+}
+
+func (l *logRecorder) Debug(msg string, kv ...any) { l.Info(msg, kv...) }
+
+func (l *logRecorder) WithValues(...any) logging.Logger { return l }
+
+func TestClient_WireLog(t *testing.T) {
+	ts := httptest.NewServer(withVersion("25.3.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"etag"`)
+		if r.Method == http.MethodGet {
+			fmt.Fprintln(w, `{"name": "ops", "type": "plugin", "attributes": {"auth_config_id": "ldap"}}`)
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprintln(w, `{"message": "Validation failed.", "data": {"name": "ops", "type": "plugin", "attributes": {"auth_config_id": "ldap", "properties": [{"key": "Password", "encrypted_value": "AES:abc", "errors": {"encrypted_value": ["Could not decrypt the value."]}}]}}}`)
+	}))
+	defer ts.Close()
+
+	log := &logRecorder{}
+	client, err := gocd.New(gocd.Config{BaseURL: ts.URL, Token: "t0ken", Logger: log, MaxLogBodyBytes: 200})
+	if err != nil {
+		t.Fatalf("New(...): unexpected error: %v", err)
+	}
+	role := gocd.Role{Name: "ops", Type: "plugin", Attributes: &gocd.RoleAttributes{
+		AuthConfigID: "ldap",
+		Properties:   []gocd.ConfigProperty{{Key: "Password", Value: "hunter2"}, {Key: "MemberOf", Value: "ou=ops"}},
+	}}
+	if _, _, err := client.Roles().Update(context.Background(), "ops", role, "etag"); err == nil {
+		t.Fatal("Roles.Update(...): expected a validation error")
+	}
+
+	// The server version is detected before the first request, and the role
+	// is read before it is updated.
+	if len(log.entries) != 3 {
+		t.Fatalf("Expected 3 logged requests, got %d", len(log.entries))
+	}
+	e := log.entries[2]
+	if e["method"] != http.MethodPut || e["path"] != "/go/api/admin/security/roles/ops" || e["status"] != http.StatusUnprocessableEntity {
+		t.Errorf("Expected the PUT and its status to be logged, got %v", e)
+	}
+	if _, ok := e["latency"]; !ok {
+		t.Errorf("Expected the latency to be logged, got %v", e)
+	}
+	if got := e["requestHeaders"].(map[string]string)["Authorization"]; got != gocd.Redacted {
+		t.Errorf("Authorization: want %q, got %q", gocd.Redacted, got)
+	}
+
+	req, resp := e["requestBody"].(string), e["responseBody"].(string)
+	for _, secret := range []string{"t0ken", "hunter2", "AES:abc"} {
+		if strings.Contains(req, secret) || strings.Contains(resp, secret) {
+			t.Errorf("Expected %q to be redacted, got request %s and response %s", secret, req, resp)
+		}
+	}
+	if !strings.Contains(req, `"value":"REDACTED"`) || !strings.Contains(req, `ou=ops`) {
+		t.Errorf("Expected only the password to be redacted, got %s", req)
+	}
+	if !strings.Contains(resp, "bytes truncated") || !strings.HasPrefix(resp, `{"data":`) {
+		t.Errorf("Expected the response body to be truncated, got %s", resp)
+	}
+}
+
+func TestClient_WireLogEncrypt(t *testing.T) {
+	ts := httptest.NewServer(withVersion("25.3.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"encrypted_value": "AES:abc"}`)
+	}))
+	defer ts.Close()
+
+	log := &logRecorder{}
+	client, err := gocd.New(gocd.Config{BaseURL: ts.URL, Token: "t0ken", Logger: log})
+	if err != nil {
+		t.Fatalf("New(...): unexpected error: %v", err)
+	}
+	if _, err := client.Encryption().Encrypt(context.Background(), "hunter2"); err != nil {
+		t.Fatalf("Encryption.Encrypt(...): unexpected error: %v", err)
+	}
+
+	// The server version is detected before the first request.
+	if len(log.entries) != 2 {
+		t.Fatalf("Expected 2 logged requests, got %d", len(log.entries))
+	}
+	e := log.entries[1]
+	if got := fmt.Sprint(e); strings.Contains(got, "hunter2") {
+		t.Errorf("Expected the encrypted value to be redacted, got %s", got)
+	}
+	if e["requestBody"] != gocd.Redacted {
+		t.Errorf("requestBody: want %q, got %q", gocd.Redacted, e["requestBody"])
+	}
+}
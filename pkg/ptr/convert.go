@@ -4,3 +4,12 @@ package ptr
 func ToPtr[T any](v T) *T {
 	return &v
 }
+
+// Deref returns the value p points to, or the zero value of T if p is nil.
+func Deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}